@@ -12,7 +12,7 @@ const (
 	PublicKeyLen  = 32
 	SignatureLen  = 64
 	seedLen       = 32
-	addressLen    = 20
+	AddressLen    = 20
 )
 
 type PrivateKey struct {
@@ -95,7 +95,7 @@ func PublicKeyFromBytes(b []byte) *PublicKey {
 
 func (p *PublicKey) Address() Address {
 	return Address{
-		value: p.key[len(p.key)-addressLen:],
+		value: p.key[len(p.key)-AddressLen:],
 	}
 }
 
@@ -120,7 +120,7 @@ type Address struct {
 }
 
 func AddressFromBytes(b []byte) Address {
-	if len(b) != addressLen {
+	if len(b) != AddressLen {
 		panic("invalid address")
 	}
 
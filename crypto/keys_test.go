@@ -47,5 +47,5 @@ func TestPublicKeyToAddress(t *testing.T) {
 	publicKey := privateKey.Public()
 	address := publicKey.Address()
 
-	require.Equal(t, addressLen, len(address.Bytes()))
+	require.Equal(t, AddressLen, len(address.Bytes()))
 }
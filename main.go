@@ -2,32 +2,72 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/blockchain/crypto"
 	blockchain "github.com/blockchain/proto"
 	"github.com/blockchain/server"
-	"github.com/blockchain/util"
+	"github.com/blockchain/types"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// genesisSeed is the seed of the key owning the genesis output, see
+// createGenesisBlock in server/chain.go.
+const genesisSeed = "ca2c1cdf74722ada1e4d152c96a8d2b184a656907b697bd3fd2e1e8abc377da9"
+
+// node is a running server along with the disk store holding its chain, nil
+// when the chain is kept in memory.
+type node struct {
+	server *server.Server
+	store  *server.DiskStore
+}
+
 func main() {
-	makeServer(":3000", []string{}, true)
+	dataDir := flag.String("datadir", "", "directory the nodes keep their chain in across restarts, empty to keep it in memory")
+	flag.Parse()
+
+	nodes := []*node{makeServer(*dataDir, ":3000", []string{}, true)}
 	time.Sleep(time.Second)
-	makeServer(":4000", []string{":3000"}, false)
+	nodes = append(nodes, makeServer(*dataDir, ":4000", []string{":3000"}, false))
 
 	time.Sleep(time.Second)
-	makeServer(":5000", []string{":4000"}, false)
+	nodes = append(nodes, makeServer(*dataDir, ":5000", []string{":4000"}, false))
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	ticker := time.NewTicker(time.Second * 2)
 
 	for {
-		time.Sleep(time.Second * 2)
-		makeTransaction()
+		select {
+		case <-ticker.C:
+			makeTransaction()
+		case <-signals:
+			for _, node := range nodes {
+				if err := node.server.Stop(); err != nil {
+					log.Println(err)
+				}
+				if node.store != nil {
+					if err := node.store.Close(); err != nil {
+						log.Println(err)
+					}
+				}
+			}
+			return
+		}
 	}
 }
 
-func makeServer(listenAddress string, bootstrapServers []string, isValidator bool) *server.Server {
+// makeServer starts a node, restoring its chain from a directory named after
+// its port under dataDir unless dataDir is empty.
+func makeServer(dataDir, listenAddress string, bootstrapServers []string, isValidator bool) *node {
 	serverConfig := server.ServerConfig{
 		Version:       "Blocker-1",
 		ListenAddress: listenAddress,
@@ -37,10 +77,30 @@ func makeServer(listenAddress string, bootstrapServers []string, isValidator boo
 		serverConfig.PrivateKey = crypto.GeneratePrivateKey()
 	}
 
-	server := server.NewServer(serverConfig)
-	go server.Start(listenAddress, bootstrapServers)
+	n := &node{}
+	var chain *server.Chain
+	if dataDir == "" {
+		chain = server.NewChain(server.NewMemoryBlockStore(), server.NewMemoryTxStore(), server.NewMemoryUTXOStore())
+	} else {
+		dir := filepath.Join(dataDir, strings.TrimPrefix(listenAddress, ":"))
+		store, err := server.OpenDiskStore(server.DiskStoreConfig{Dir: dir})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		chain, err = server.OpenChain(store.Blocks, store.Transactions, store.UTXOs)
+		if err != nil {
+			log.Fatal(err)
+		}
 
-	return server
+		n.store = store
+		serverConfig.MempoolFile = filepath.Join(dir, "mempool.dat")
+	}
+
+	n.server = server.NewServer(serverConfig, chain)
+	go n.server.Start(listenAddress, bootstrapServers)
+
+	return n
 }
 
 func makeTransaction() {
@@ -51,29 +111,52 @@ func makeTransaction() {
 	defer conn.Close()
 	c := blockchain.NewBlockChainClient(conn)
 
-	privateKey := crypto.GeneratePrivateKey()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	privateKey := crypto.NewPrivateKeyFromString(genesisSeed)
+	address := privateKey.Public().Address().Bytes()
+
+	unspent, err := c.ListUnspent(ctx, &blockchain.AddressRequest{Address: address})
+	if err != nil {
+		log.Fatal(err)
+	}
+	var output *blockchain.Unspent
+	for _, candidate := range unspent.Outputs {
+		if !candidate.Staked {
+			output = candidate
+			break
+		}
+	}
+	if output == nil {
+		log.Println("no funds left to spend")
+		return
+	}
+
 	transaction := &blockchain.Transaction{
 		Version: 1,
 		Inputs: []*blockchain.TxInput{
 			{
-				PreviousTxHash:   util.RandomHash(),
-				PreviousOutIndex: 0,
+				PreviousTxHash:   output.OutPoint.TxHash,
+				PreviousOutIndex: output.OutPoint.Index,
 				PublicKey:        privateKey.Public().Bytes(),
 			},
 		},
 		Outputs: []*blockchain.TxOutput{
 			{
-				Amount:  99,
-				Address: privateKey.Public().Address().Bytes(),
+				Amount:  1,
+				Address: crypto.GeneratePrivateKey().Public().Address().Bytes(),
+			},
+			{
+				Amount:  output.Amount - 1,
+				Address: address,
 			},
 		},
 	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	transaction.Inputs[0].Signature = types.SignTransaction(privateKey, transaction).Bytes()
 
 	_, err = c.HandleTransaction(ctx, transaction)
 	if err != nil {
-		log.Fatal(err)
+		log.Println(err)
 	}
 }
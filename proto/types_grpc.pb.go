@@ -24,6 +24,22 @@ const _ = grpc.SupportPackageIsVersion7
 type BlockChainClient interface {
 	Handshake(ctx context.Context, in *HandshakeMessage, opts ...grpc.CallOption) (*HandshakeMessage, error)
 	HandleTransaction(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*Ack, error)
+	SubmitPackage(ctx context.Context, in *Package, opts ...grpc.CallOption) (*Ack, error)
+	HandleBlock(ctx context.Context, in *Block, opts ...grpc.CallOption) (*Ack, error)
+	HandleVote(ctx context.Context, in *Vote, opts ...grpc.CallOption) (*Ack, error)
+	GetCommit(ctx context.Context, in *GetCommitRequest, opts ...grpc.CallOption) (*Commit, error)
+	GetHeaders(ctx context.Context, in *GetHeadersRequest, opts ...grpc.CallOption) (BlockChain_GetHeadersClient, error)
+	GetBlocks(ctx context.Context, in *GetBlocksRequest, opts ...grpc.CallOption) (BlockChain_GetBlocksClient, error)
+	GetBlockByHash(ctx context.Context, in *GetBlockByHashRequest, opts ...grpc.CallOption) (*Block, error)
+	GetBlockByHeight(ctx context.Context, in *GetBlockByHeightRequest, opts ...grpc.CallOption) (*Block, error)
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*TransactionInfo, error)
+	GetChainInfo(ctx context.Context, in *GetChainInfoRequest, opts ...grpc.CallOption) (*ChainInfo, error)
+	GetBalance(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*Balance, error)
+	ListUnspent(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*UnspentList, error)
+	GetSupply(ctx context.Context, in *GetSupplyRequest, opts ...grpc.CallOption) (*Supply, error)
+	GetMempoolInfo(ctx context.Context, in *GetMempoolInfoRequest, opts ...grpc.CallOption) (*MempoolInfo, error)
+	GetMempoolEntry(ctx context.Context, in *GetMempoolEntryRequest, opts ...grpc.CallOption) (*MempoolEntry, error)
+	SubscribeMempool(ctx context.Context, in *SubscribeMempoolRequest, opts ...grpc.CallOption) (BlockChain_SubscribeMempoolClient, error)
 }
 
 type blockChainClient struct {
@@ -52,12 +68,241 @@ func (c *blockChainClient) HandleTransaction(ctx context.Context, in *Transactio
 	return out, nil
 }
 
+func (c *blockChainClient) SubmitPackage(ctx context.Context, in *Package, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, "/BlockChain/SubmitPackage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) HandleBlock(ctx context.Context, in *Block, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, "/BlockChain/HandleBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) HandleVote(ctx context.Context, in *Vote, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, "/BlockChain/HandleVote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) GetCommit(ctx context.Context, in *GetCommitRequest, opts ...grpc.CallOption) (*Commit, error) {
+	out := new(Commit)
+	err := c.cc.Invoke(ctx, "/BlockChain/GetCommit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) GetHeaders(ctx context.Context, in *GetHeadersRequest, opts ...grpc.CallOption) (BlockChain_GetHeadersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BlockChain_ServiceDesc.Streams[0], "/BlockChain/GetHeaders", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockChainGetHeadersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlockChain_GetHeadersClient interface {
+	Recv() (*Header, error)
+	grpc.ClientStream
+}
+
+type blockChainGetHeadersClient struct {
+	grpc.ClientStream
+}
+
+func (x *blockChainGetHeadersClient) Recv() (*Header, error) {
+	m := new(Header)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blockChainClient) GetBlocks(ctx context.Context, in *GetBlocksRequest, opts ...grpc.CallOption) (BlockChain_GetBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BlockChain_ServiceDesc.Streams[1], "/BlockChain/GetBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockChainGetBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlockChain_GetBlocksClient interface {
+	Recv() (*Block, error)
+	grpc.ClientStream
+}
+
+type blockChainGetBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *blockChainGetBlocksClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blockChainClient) GetBlockByHash(ctx context.Context, in *GetBlockByHashRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/BlockChain/GetBlockByHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) GetBlockByHeight(ctx context.Context, in *GetBlockByHeightRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/BlockChain/GetBlockByHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*TransactionInfo, error) {
+	out := new(TransactionInfo)
+	err := c.cc.Invoke(ctx, "/BlockChain/GetTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) GetChainInfo(ctx context.Context, in *GetChainInfoRequest, opts ...grpc.CallOption) (*ChainInfo, error) {
+	out := new(ChainInfo)
+	err := c.cc.Invoke(ctx, "/BlockChain/GetChainInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) GetBalance(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*Balance, error) {
+	out := new(Balance)
+	err := c.cc.Invoke(ctx, "/BlockChain/GetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) ListUnspent(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*UnspentList, error) {
+	out := new(UnspentList)
+	err := c.cc.Invoke(ctx, "/BlockChain/ListUnspent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) GetSupply(ctx context.Context, in *GetSupplyRequest, opts ...grpc.CallOption) (*Supply, error) {
+	out := new(Supply)
+	err := c.cc.Invoke(ctx, "/BlockChain/GetSupply", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) GetMempoolInfo(ctx context.Context, in *GetMempoolInfoRequest, opts ...grpc.CallOption) (*MempoolInfo, error) {
+	out := new(MempoolInfo)
+	err := c.cc.Invoke(ctx, "/BlockChain/GetMempoolInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) GetMempoolEntry(ctx context.Context, in *GetMempoolEntryRequest, opts ...grpc.CallOption) (*MempoolEntry, error) {
+	out := new(MempoolEntry)
+	err := c.cc.Invoke(ctx, "/BlockChain/GetMempoolEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockChainClient) SubscribeMempool(ctx context.Context, in *SubscribeMempoolRequest, opts ...grpc.CallOption) (BlockChain_SubscribeMempoolClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BlockChain_ServiceDesc.Streams[2], "/BlockChain/SubscribeMempool", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockChainSubscribeMempoolClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlockChain_SubscribeMempoolClient interface {
+	Recv() (*MempoolEvent, error)
+	grpc.ClientStream
+}
+
+type blockChainSubscribeMempoolClient struct {
+	grpc.ClientStream
+}
+
+func (x *blockChainSubscribeMempoolClient) Recv() (*MempoolEvent, error) {
+	m := new(MempoolEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // BlockChainServer is the server API for BlockChain service.
 // All implementations must embed UnimplementedBlockChainServer
 // for forward compatibility
 type BlockChainServer interface {
 	Handshake(context.Context, *HandshakeMessage) (*HandshakeMessage, error)
 	HandleTransaction(context.Context, *Transaction) (*Ack, error)
+	SubmitPackage(context.Context, *Package) (*Ack, error)
+	HandleBlock(context.Context, *Block) (*Ack, error)
+	HandleVote(context.Context, *Vote) (*Ack, error)
+	GetCommit(context.Context, *GetCommitRequest) (*Commit, error)
+	GetHeaders(*GetHeadersRequest, BlockChain_GetHeadersServer) error
+	GetBlocks(*GetBlocksRequest, BlockChain_GetBlocksServer) error
+	GetBlockByHash(context.Context, *GetBlockByHashRequest) (*Block, error)
+	GetBlockByHeight(context.Context, *GetBlockByHeightRequest) (*Block, error)
+	GetTransaction(context.Context, *GetTransactionRequest) (*TransactionInfo, error)
+	GetChainInfo(context.Context, *GetChainInfoRequest) (*ChainInfo, error)
+	GetBalance(context.Context, *AddressRequest) (*Balance, error)
+	ListUnspent(context.Context, *AddressRequest) (*UnspentList, error)
+	GetSupply(context.Context, *GetSupplyRequest) (*Supply, error)
+	GetMempoolInfo(context.Context, *GetMempoolInfoRequest) (*MempoolInfo, error)
+	GetMempoolEntry(context.Context, *GetMempoolEntryRequest) (*MempoolEntry, error)
+	SubscribeMempool(*SubscribeMempoolRequest, BlockChain_SubscribeMempoolServer) error
 	mustEmbedUnimplementedBlockChainServer()
 }
 
@@ -71,6 +316,54 @@ func (UnimplementedBlockChainServer) Handshake(context.Context, *HandshakeMessag
 func (UnimplementedBlockChainServer) HandleTransaction(context.Context, *Transaction) (*Ack, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HandleTransaction not implemented")
 }
+func (UnimplementedBlockChainServer) SubmitPackage(context.Context, *Package) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitPackage not implemented")
+}
+func (UnimplementedBlockChainServer) HandleBlock(context.Context, *Block) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleBlock not implemented")
+}
+func (UnimplementedBlockChainServer) HandleVote(context.Context, *Vote) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleVote not implemented")
+}
+func (UnimplementedBlockChainServer) GetCommit(context.Context, *GetCommitRequest) (*Commit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCommit not implemented")
+}
+func (UnimplementedBlockChainServer) GetHeaders(*GetHeadersRequest, BlockChain_GetHeadersServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetHeaders not implemented")
+}
+func (UnimplementedBlockChainServer) GetBlocks(*GetBlocksRequest, BlockChain_GetBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetBlocks not implemented")
+}
+func (UnimplementedBlockChainServer) GetBlockByHash(context.Context, *GetBlockByHashRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockByHash not implemented")
+}
+func (UnimplementedBlockChainServer) GetBlockByHeight(context.Context, *GetBlockByHeightRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockByHeight not implemented")
+}
+func (UnimplementedBlockChainServer) GetTransaction(context.Context, *GetTransactionRequest) (*TransactionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransaction not implemented")
+}
+func (UnimplementedBlockChainServer) GetChainInfo(context.Context, *GetChainInfoRequest) (*ChainInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChainInfo not implemented")
+}
+func (UnimplementedBlockChainServer) GetBalance(context.Context, *AddressRequest) (*Balance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedBlockChainServer) ListUnspent(context.Context, *AddressRequest) (*UnspentList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUnspent not implemented")
+}
+func (UnimplementedBlockChainServer) GetSupply(context.Context, *GetSupplyRequest) (*Supply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSupply not implemented")
+}
+func (UnimplementedBlockChainServer) GetMempoolInfo(context.Context, *GetMempoolInfoRequest) (*MempoolInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMempoolInfo not implemented")
+}
+func (UnimplementedBlockChainServer) GetMempoolEntry(context.Context, *GetMempoolEntryRequest) (*MempoolEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMempoolEntry not implemented")
+}
+func (UnimplementedBlockChainServer) SubscribeMempool(*SubscribeMempoolRequest, BlockChain_SubscribeMempoolServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeMempool not implemented")
+}
 func (UnimplementedBlockChainServer) mustEmbedUnimplementedBlockChainServer() {}
 
 // UnsafeBlockChainServer may be embedded to opt out of forward compatibility for this service.
@@ -120,6 +413,303 @@ func _BlockChain_HandleTransaction_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlockChain_SubmitPackage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Package)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).SubmitPackage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/SubmitPackage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).SubmitPackage(ctx, req.(*Package))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_HandleBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Block)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).HandleBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/HandleBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).HandleBlock(ctx, req.(*Block))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_HandleVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Vote)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).HandleVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/HandleVote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).HandleVote(ctx, req.(*Vote))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_GetCommit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCommitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).GetCommit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/GetCommit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).GetCommit(ctx, req.(*GetCommitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_GetHeaders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetHeadersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockChainServer).GetHeaders(m, &blockChainGetHeadersServer{stream})
+}
+
+type BlockChain_GetHeadersServer interface {
+	Send(*Header) error
+	grpc.ServerStream
+}
+
+type blockChainGetHeadersServer struct {
+	grpc.ServerStream
+}
+
+func (x *blockChainGetHeadersServer) Send(m *Header) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BlockChain_GetBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockChainServer).GetBlocks(m, &blockChainGetBlocksServer{stream})
+}
+
+type BlockChain_GetBlocksServer interface {
+	Send(*Block) error
+	grpc.ServerStream
+}
+
+type blockChainGetBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *blockChainGetBlocksServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BlockChain_GetBlockByHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockByHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).GetBlockByHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/GetBlockByHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).GetBlockByHash(ctx, req.(*GetBlockByHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_GetBlockByHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockByHeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).GetBlockByHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/GetBlockByHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).GetBlockByHeight(ctx, req.(*GetBlockByHeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_GetChainInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChainInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).GetChainInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/GetChainInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).GetChainInfo(ctx, req.(*GetChainInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).GetBalance(ctx, req.(*AddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_ListUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).ListUnspent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/ListUnspent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).ListUnspent(ctx, req.(*AddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_GetSupply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSupplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).GetSupply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/GetSupply",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).GetSupply(ctx, req.(*GetSupplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_GetMempoolInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMempoolInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).GetMempoolInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/GetMempoolInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).GetMempoolInfo(ctx, req.(*GetMempoolInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_GetMempoolEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMempoolEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockChainServer).GetMempoolEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockChain/GetMempoolEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockChainServer).GetMempoolEntry(ctx, req.(*GetMempoolEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockChain_SubscribeMempool_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeMempoolRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockChainServer).SubscribeMempool(m, &blockChainSubscribeMempoolServer{stream})
+}
+
+type BlockChain_SubscribeMempoolServer interface {
+	Send(*MempoolEvent) error
+	grpc.ServerStream
+}
+
+type blockChainSubscribeMempoolServer struct {
+	grpc.ServerStream
+}
+
+func (x *blockChainSubscribeMempoolServer) Send(m *MempoolEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // BlockChain_ServiceDesc is the grpc.ServiceDesc for BlockChain service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -135,7 +725,75 @@ var BlockChain_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "HandleTransaction",
 			Handler:    _BlockChain_HandleTransaction_Handler,
 		},
+		{
+			MethodName: "SubmitPackage",
+			Handler:    _BlockChain_SubmitPackage_Handler,
+		},
+		{
+			MethodName: "HandleBlock",
+			Handler:    _BlockChain_HandleBlock_Handler,
+		},
+		{
+			MethodName: "HandleVote",
+			Handler:    _BlockChain_HandleVote_Handler,
+		},
+		{
+			MethodName: "GetCommit",
+			Handler:    _BlockChain_GetCommit_Handler,
+		},
+		{
+			MethodName: "GetBlockByHash",
+			Handler:    _BlockChain_GetBlockByHash_Handler,
+		},
+		{
+			MethodName: "GetBlockByHeight",
+			Handler:    _BlockChain_GetBlockByHeight_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _BlockChain_GetTransaction_Handler,
+		},
+		{
+			MethodName: "GetChainInfo",
+			Handler:    _BlockChain_GetChainInfo_Handler,
+		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _BlockChain_GetBalance_Handler,
+		},
+		{
+			MethodName: "ListUnspent",
+			Handler:    _BlockChain_ListUnspent_Handler,
+		},
+		{
+			MethodName: "GetSupply",
+			Handler:    _BlockChain_GetSupply_Handler,
+		},
+		{
+			MethodName: "GetMempoolInfo",
+			Handler:    _BlockChain_GetMempoolInfo_Handler,
+		},
+		{
+			MethodName: "GetMempoolEntry",
+			Handler:    _BlockChain_GetMempoolEntry_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetHeaders",
+			Handler:       _BlockChain_GetHeaders_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetBlocks",
+			Handler:       _BlockChain_GetBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeMempool",
+			Handler:       _BlockChain_SubscribeMempool_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/types.proto",
 }
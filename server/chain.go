@@ -2,8 +2,13 @@ package server
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/blockchain/crypto"
 	blockchain "github.com/blockchain/proto"
@@ -13,6 +18,7 @@ import (
 const seed = "ca2c1cdf74722ada1e4d152c96a8d2b184a656907b697bd3fd2e1e8abc377da9"
 
 type HeaderList struct {
+	lock    sync.RWMutex
 	headers []*blockchain.Header
 }
 
@@ -23,11 +29,26 @@ func NewHeaderList() *HeaderList {
 }
 
 func (list *HeaderList) Add(header *blockchain.Header) {
+	list.lock.Lock()
+	defer list.lock.Unlock()
+
 	list.headers = append(list.headers, header)
 }
 
+func (list *HeaderList) Pop() *blockchain.Header {
+	list.lock.Lock()
+	defer list.lock.Unlock()
+
+	header := list.headers[len(list.headers)-1]
+	list.headers = list.headers[:len(list.headers)-1]
+	return header
+}
+
 func (list *HeaderList) Get(index int) *blockchain.Header {
-	if index > list.Height() {
+	list.lock.RLock()
+	defer list.lock.RUnlock()
+
+	if index >= len(list.headers) {
 		panic("index  too high")
 	}
 	return list.headers[index]
@@ -38,85 +59,623 @@ func (list *HeaderList) Height() int {
 }
 
 func (list *HeaderList) Len() int {
+	list.lock.RLock()
+	defer list.lock.RUnlock()
+
 	return len(list.headers)
 }
 
 type UTXO struct {
-	Hash     string
-	OutIndex int
+	OutPoint types.OutPoint
 	Amount   int64
+	Address  string
 	Spent    bool
+	// Staked outputs count towards the stake of their owner and may only be
+	// spent by an unstake transaction.
+	Staked bool
+}
+
+// outputUTXO returns the output at the given index of the transaction with
+// the given hash.
+func outputUTXO(tx *blockchain.Transaction, hash []byte, index int) *UTXO {
+	output := tx.Outputs[index]
+
+	return &UTXO{
+		OutPoint: types.NewOutPoint(hash, uint32(index)),
+		Amount:   output.Amount,
+		Address:  hex.EncodeToString(output.Address),
+		Staked:   tx.Kind == blockchain.Transaction_STAKE,
+	}
 }
 
 type Chain struct {
+	lock       sync.Mutex
 	txStore    TXStorer
 	blockStore BlockStorer
 	utxoStore  UTXOStorer
 	headers    *HeaderList
+
+	index map[string]*BlockNode
+	// invalid remembers the blocks dropped for failing to connect and their
+	// descendants, so that they are not retried.
+	invalid    *hashSet
+	tip        *BlockNode
+	finalized  *BlockNode
+	forkChoice ForkChoice
+	policy     MonetaryPolicy
+	genesis    Genesis
+	listeners  []ChainListener
+
+	// stakes holds the bonded stake of every address, it is changed under
+	// both locks and read by the mempool under stakeLock alone.
+	stakeLock sync.RWMutex
+	stakes    map[string]int64
+
+	// slashed holds the height at which each convicted validator was slashed,
+	// it is read by the mempool without the chain lock.
+	slashLock sync.RWMutex
+	slashed   map[string]int
+}
+
+type ChainOption func(*Chain)
+
+func WithForkChoice(rule ForkChoice) ChainOption {
+	return func(chain *Chain) {
+		chain.forkChoice = rule
+	}
+}
+
+type ChainCorruptionError struct {
+	Hash   string
+	Reason string
+}
+
+func (err *ChainCorruptionError) Error() string {
+	return fmt.Sprintf("corrupt chain at block [%s]: %s", err.Hash, err.Reason)
+}
+
+func NewChain(blockStorer BlockStorer, txStorer TXStorer, utxoStore UTXOStorer, opts ...ChainOption) *Chain {
+	chain, err := OpenChain(blockStorer, txStorer, utxoStore, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return chain
 }
 
-func NewChain(blockStorer BlockStorer, txStorer TXStorer, utxoStore UTXOStorer) *Chain {
+// OpenChain restores the chain held by the given stores, or initializes them
+// with the genesis block when they are empty.
+func OpenChain(blockStorer BlockStorer, txStorer TXStorer, utxoStore UTXOStorer, opts ...ChainOption) (*Chain, error) {
 	chain := &Chain{
 		txStore:    txStorer,
 		blockStore: blockStorer,
 		utxoStore:  utxoStore,
 		headers:    NewHeaderList(),
+		index:      make(map[string]*BlockNode),
+		invalid:    newHashSet(maxInvalidBlocks),
+		stakes:     make(map[string]int64),
+		slashed:    make(map[string]int),
+		policy:     DefaultMonetaryPolicy,
 	}
-	chain.addBlock(createGenesisBlock())
 
-	return chain
+	for _, opt := range opts {
+		opt(chain)
+	}
+
+	if chain.forkChoice == nil {
+		chain.forkChoice = LongestChain{}
+		if chain.genesis.Consensus == ConsensusWork {
+			chain.forkChoice = MostWork{}
+		}
+	}
+
+	if err := chain.recoverPending(); err != nil {
+		return nil, err
+	}
+
+	tip, err := blockStorer.Tip()
+	if err != nil {
+		return nil, err
+	}
+
+	if tip == "" {
+		genesis := createGenesisBlock(chain.genesis, chain.policy)
+		if err := chain.connectBlock(chain.addNode(genesis, nil), genesis); err != nil {
+			return nil, err
+		}
+		chain.finalized = chain.tip
+		return chain, nil
+	}
+
+	if err := chain.loadHeaders(tip); err != nil {
+		return nil, err
+	}
+
+	if err := chain.loadStakes(); err != nil {
+		return nil, err
+	}
+
+	return chain, nil
+}
+
+// loadHeaders rebuilds the header list by walking the PreviousHash links from
+// the stored tip back to the genesis block.
+func (chain *Chain) loadHeaders(tip string) error {
+	headers := []*blockchain.Header{}
+	proposers := [][]byte{}
+	offenders := make(map[string]int)
+	hash := tip
+
+	for {
+		block, err := chain.blockStore.Get(hash)
+		if err != nil {
+			return &ChainCorruptionError{Hash: hash, Reason: "block is missing from the store"}
+		}
+
+		if hex.EncodeToString(types.HashBlock(block)) != hash {
+			return &ChainCorruptionError{Hash: hash, Reason: "stored block does not match its hash"}
+		}
+
+		if err := chain.genesis.verifyBlock(block); err != nil {
+			return &ChainCorruptionError{Hash: hash, Reason: err.Error()}
+		}
+
+		if err := validateBlockSize(block); err != nil {
+			return &ChainCorruptionError{Hash: hash, Reason: err.Error()}
+		}
+
+		headers = append(headers, block.Header)
+		proposers = append(proposers, block.PublicKey)
+		for _, tx := range block.Transactions {
+			if tx.Kind == blockchain.Transaction_EVIDENCE && tx.Evidence != nil {
+				offenders[offender(tx.Evidence)] = len(headers) - 1
+			}
+		}
+
+		if len(block.Header.PreviousHash) == 0 {
+			break
+		}
+		hash = hex.EncodeToString(block.Header.PreviousHash)
+	}
+
+	genesisHash := hex.EncodeToString(types.HashBlock(createGenesisBlock(chain.genesis, chain.policy)))
+	if hash != genesisHash {
+		return &ChainCorruptionError{Hash: hash, Reason: fmt.Sprintf("chain does not start at genesis block [%s]", genesisHash)}
+	}
+
+	// offenders were indexed from the tip down, the schedule checked below
+	// depends on them
+	for address, i := range offenders {
+		chain.slashed[address] = len(headers) - 1 - i
+	}
+
+	// the header rules are checked again now that the parents are known, the
+	// transactions were held to their limits while walking back
+	var (
+		parent *BlockNode
+		now    = time.Now()
+	)
+	for i := len(headers) - 1; i >= 0; i-- {
+		height := len(headers) - 1 - i
+		if height > 0 {
+			if err := validateHeader(&blockchain.Block{Header: headers[i]}, parent, now); err != nil {
+				return &ChainCorruptionError{Hash: hex.EncodeToString(types.HashHeader(headers[i])), Reason: err.Error()}
+			}
+		}
+
+		if proposer := chain.proposer(height); height > 0 && proposer != nil && !bytes.Equal(proposers[i], proposer.Bytes()) {
+			return &ChainCorruptionError{Hash: hex.EncodeToString(types.HashHeader(headers[i])), Reason: "block is not signed by the scheduled proposer"}
+		}
+
+		if height > 0 && chain.genesis.Consensus == ConsensusWork {
+			if err := chain.checkWork(headers[i], parent); err != nil {
+				return &ChainCorruptionError{Hash: hex.EncodeToString(types.HashHeader(headers[i])), Reason: err.Error()}
+			}
+		}
+
+		parent = chain.addNode(&blockchain.Block{Header: headers[i]}, parent)
+		chain.headers.Add(headers[i])
+	}
+	chain.tip = parent
+
+	for node := chain.tip; node != nil; node = node.Parent {
+		commit, err := chain.blockStore.GetCommit(node.Hash)
+		if err != nil {
+			return err
+		}
+		if commit != nil || node.Parent == nil {
+			chain.finalized = node
+			break
+		}
+	}
+
+	return nil
+}
+
+// CanPropose reports whether the key may sign the block extending the tip.
+func (chain *Chain) CanPropose(publicKey *crypto.PublicKey) bool {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	if chain.genesis.Consensus == ConsensusStake {
+		staker := chain.stakeProposer(types.HashHeader(chain.tip.Header))
+		return staker == "" || staker == publicKey.Address().String()
+	}
+
+	proposer := chain.proposer(chain.tip.Height + 1)
+	return proposer == nil || bytes.Equal(proposer.Bytes(), publicKey.Bytes())
+}
+
+// Proposer returns the validator scheduled to propose the block at the given
+// height, or nil when any key may propose it. Slashed validators are skipped.
+func (chain *Chain) Proposer(height int) *crypto.PublicKey {
+	return chain.proposer(height)
 }
 
 func (chain *Chain) Height() int {
 	return chain.headers.Height()
 }
 
+// Subscribe registers a listener that is notified, while the chain is locked,
+// of every block connected to or disconnected from the main chain.
+func (chain *Chain) Subscribe(listener ChainListener) {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	chain.listeners = append(chain.listeners, listener)
+}
+
+// AddBlock adds the block to the block tree. A block extending the tip is
+// connected right away, a block on a side branch is stored and triggers a
+// reorganisation once the fork choice rule prefers its branch.
 func (chain *Chain) AddBlock(block *blockchain.Block) error {
-	if err := chain.ValidateBlock(block); err != nil {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	if err := chain.validateBlock(block); err != nil {
 		return err
 	}
 
-	return chain.addBlock(block)
+	parent := chain.index[hex.EncodeToString(block.Header.PreviousHash)]
+	node := chain.addNode(block, parent)
+
+	if parent == chain.tip {
+		if err := chain.connectBlock(node, block); err != nil {
+			delete(chain.index, node.Hash)
+			return err
+		}
+		return nil
+	}
+
+	if err := chain.blockStore.Put(block); err != nil {
+		delete(chain.index, node.Hash)
+		return err
+	}
+
+	if chain.forkChoice.Better(node, chain.tip) {
+		return chain.reorganize(node)
+	}
+
+	return nil
 }
 
-func (chain *Chain) addBlock(block *blockchain.Block) error {
-	chain.headers.Add(block.Header)
+func (chain *Chain) addNode(block *blockchain.Block, parent *BlockNode) *BlockNode {
+	node := &BlockNode{
+		Hash:   hex.EncodeToString(types.HashBlock(block)),
+		Header: block.Header,
+		Parent: parent,
+		Work:   new(big.Int).SetUint64(block.Header.Difficulty),
+	}
+	if parent != nil {
+		node.Height = parent.Height + 1
+		node.Work.Add(node.Work, parent.Work)
+	}
+
+	chain.index[node.Hash] = node
+	return node
+}
+
+// connectBlock applies the transactions of a block extending the tip to the
+// stores and makes it the new tip.
+func (chain *Chain) connectBlock(node *BlockNode, block *blockchain.Block) error {
+	undo, err := chain.blockUndo(block)
+	if err != nil {
+		return err
+	}
+
+	// the block and its undo data are written before the pending marker, so
+	// that OpenChain can revert the block if the node stops halfway
+	if err := chain.blockStore.Put(block); err != nil {
+		return err
+	}
+
+	if err := chain.blockStore.PutUndo(node.Hash, undo); err != nil {
+		return err
+	}
+
+	if err := chain.blockStore.SetPending(node.Hash); err != nil {
+		return err
+	}
+
+	// the stores are flushed on their own, the marker has to reach the disk
+	// before the outputs change, and the outputs before the tip moves
+	if err := syncStores(chain.blockStore); err != nil {
+		return err
+	}
+
+	if err := chain.applyBlock(block, undo); err != nil {
+		if revertErr := chain.revertBlock(block, undo); revertErr == nil {
+			chain.blockStore.SetPending("")
+		}
+		return err
+	}
+
+	if err := syncStores(chain.utxoStore, chain.txStore); err != nil {
+		return err
+	}
+
+	if err := chain.blockStore.SetTip(node.Hash); err != nil {
+		return err
+	}
+
+	if err := chain.blockStore.SetPending(""); err != nil {
+		return err
+	}
 
 	for _, tx := range block.Transactions {
-		if err := chain.txStore.Put(tx); err != nil {
-			return err
+		hash := types.HashTransaction(tx)
+		for index := range tx.Outputs {
+			chain.trackStake(outputUTXO(tx, hash, index), 1)
+		}
+
+		if tx.Kind == blockchain.Transaction_EVIDENCE {
+			chain.slash(tx, node.Height)
 		}
+	}
+	for _, utxo := range undo.Spent {
+		chain.trackStake(utxo, -1)
+	}
+
+	chain.headers.Add(block.Header)
+	chain.tip = node
 
-		hash := hex.EncodeToString(types.HashTransaction(tx))
+	for _, listener := range chain.listeners {
+		listener.BlockConnected(block)
+	}
+
+	return nil
+}
+
+// blockUndo works out, without touching the stores, the outputs the block
+// spends and burns as they are before it and the outputs it creates.
+func (chain *Chain) blockUndo(block *blockchain.Block) (*BlockUndo, error) {
+	undo := &BlockUndo{
+		Spent:   []*UTXO{},
+		Created: []types.OutPoint{},
+	}
+	view := newUTXOView(chain.utxoStore.Get)
+
+	for _, tx := range block.Transactions {
+		hash := types.HashTransaction(tx)
+		for index := range tx.Outputs {
+			undo.Created = append(undo.Created, types.NewOutPoint(hash, uint32(index)))
+		}
 
-		for index, output := range tx.Outputs {
-			utxo := &UTXO{
-				Hash:     hash,
-				Amount:   output.Amount,
-				OutIndex: index,
-				Spent:    false,
+		if tx.Kind == blockchain.Transaction_EVIDENCE {
+			burned, err := chain.burnedStake(tx, view)
+			if err != nil {
+				return nil, err
 			}
 
-			if err := chain.utxoStore.Put(utxo); err != nil {
-				return err
+			for _, utxo := range burned {
+				undo.Spent = append(undo.Spent, utxo)
+
+				spent := *utxo
+				spent.Spent = true
+				view.utxos[spent.OutPoint] = &spent
 			}
 		}
 
-		for index, input := range tx.Inputs {
-			key := fmt.Sprintf("%s_%d", hex.EncodeToString(input.PreviousTxHash), index)
-			utxo, err := chain.utxoStore.Get(key)
-			if err != nil {
-				return err
+		if !types.IsCoinbase(tx) {
+			for _, input := range tx.Inputs {
+				utxo, err := view.Get(types.SpentOutPoint(input))
+				if err != nil {
+					return nil, err
+				}
+				undo.Spent = append(undo.Spent, utxo)
 			}
+		}
+
+		if err := view.apply(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	return undo, nil
+}
+
+// applyBlock writes the transactions of the block, the outputs it creates and
+// the outputs of the undo data marked as spent.
+func (chain *Chain) applyBlock(block *blockchain.Block, undo *BlockUndo) error {
+	for _, tx := range block.Transactions {
+		if err := chain.txStore.Put(tx); err != nil {
+			return err
+		}
 
-			utxo.Spent = true
-			if err := chain.utxoStore.Put(utxo); err != nil {
+		hash := types.HashTransaction(tx)
+		for index := range tx.Outputs {
+			if err := chain.utxoStore.Put(outputUTXO(tx, hash, index)); err != nil {
 				return err
 			}
 		}
 	}
 
-	return chain.blockStore.Put(block)
+	for _, utxo := range undo.Spent {
+		spent := *utxo
+		spent.Spent = true
+		if err := chain.utxoStore.Put(&spent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revertBlock puts back the outputs the block spent and removes the outputs
+// and transactions it created. Reverting a block that was only partly applied
+// is fine, which lets OpenChain recover from an interrupted change.
+func (chain *Chain) revertBlock(block *blockchain.Block, undo *BlockUndo) error {
+	// outputs created and spent within the block are restored before being
+	// deleted along with the other outputs the block created
+	for i := len(undo.Spent) - 1; i >= 0; i-- {
+		if err := chain.utxoStore.Put(undo.Spent[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := len(undo.Created) - 1; i >= 0; i-- {
+		if err := chain.utxoStore.Delete(undo.Created[i]); err != nil {
+			return err
+		}
+	}
+
+	for _, tx := range block.Transactions {
+		if err := chain.txStore.Delete(hex.EncodeToString(types.HashTransaction(tx))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recoverPending reverts the block that was being connected or disconnected
+// when the node last stopped, and makes its parent the tip. A connection is
+// rolled back and a disconnection completed, so the stores agree with the tip
+// again.
+func (chain *Chain) recoverPending() error {
+	hash, err := chain.blockStore.Pending()
+	if err != nil || hash == "" {
+		return err
+	}
+
+	block, err := chain.blockStore.Get(hash)
+	if err != nil {
+		return &ChainCorruptionError{Hash: hash, Reason: "pending block is missing from the store"}
+	}
+
+	undo, err := chain.blockStore.GetUndo(hash)
+	if err != nil {
+		return &ChainCorruptionError{Hash: hash, Reason: "pending block has no undo data"}
+	}
+
+	if err := chain.revertBlock(block, undo); err != nil {
+		return err
+	}
+
+	if err := syncStores(chain.utxoStore, chain.txStore); err != nil {
+		return err
+	}
+
+	if err := chain.blockStore.SetTip(hex.EncodeToString(block.Header.PreviousHash)); err != nil {
+		return err
+	}
+
+	return chain.blockStore.SetPending("")
+}
+
+// DisconnectTip reverts the tip block using its undo data and makes its
+// parent the new tip. The block stays known as a side branch.
+func (chain *Chain) DisconnectTip() error {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	return chain.disconnectTip()
+}
+
+func (chain *Chain) disconnectTip() error {
+	node := chain.tip
+	if node.Parent == nil {
+		return fmt.Errorf("can not disconnect the genesis block")
+	}
+	if node == chain.finalized {
+		return fmt.Errorf("can not disconnect the finalized block [%s]", node.Hash)
+	}
+
+	block, err := chain.blockStore.Get(node.Hash)
+	if err != nil {
+		return err
+	}
+
+	undo, err := chain.blockStore.GetUndo(node.Hash)
+	if err != nil {
+		return err
+	}
+
+	if err := chain.blockStore.SetPending(node.Hash); err != nil {
+		return err
+	}
+
+	if err := syncStores(chain.blockStore); err != nil {
+		return err
+	}
+
+	if err := chain.revertBlock(block, undo); err != nil {
+		return err
+	}
+
+	if err := syncStores(chain.utxoStore, chain.txStore); err != nil {
+		return err
+	}
+
+	if err := chain.blockStore.SetTip(node.Parent.Hash); err != nil {
+		return err
+	}
+
+	if err := chain.blockStore.SetPending(""); err != nil {
+		return err
+	}
+
+	for _, utxo := range undo.Spent {
+		chain.trackStake(utxo, 1)
+	}
+	for _, tx := range block.Transactions {
+		hash := types.HashTransaction(tx)
+		for index := range tx.Outputs {
+			chain.trackStake(outputUTXO(tx, hash, index), -1)
+		}
+	}
+
+	chain.unslash(node.Height)
+	chain.headers.Pop()
+	chain.tip = node.Parent
+
+	for _, listener := range chain.listeners {
+		listener.BlockDisconnected(block)
+	}
+
+	return nil
+}
+
+// IsInvalid reports whether the block with the given hash was dropped for
+// being invalid or descending from an invalid block.
+func (chain *Chain) IsInvalid(hash []byte) bool {
+	return chain.invalid.Has(hex.EncodeToString(hash))
+}
+
+// Invalidate marks the block with the given hash as invalid, it is called for
+// the blocks found to descend from one.
+func (chain *Chain) Invalidate(hash []byte) {
+	chain.invalid.Add(hex.EncodeToString(hash))
+}
+
+// HasBlock reports whether the block is in the index, on the main chain or
+// not.
+func (chain *Chain) HasBlock(hash []byte) bool {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	_, ok := chain.index[hex.EncodeToString(hash)]
+	return ok
 }
 
 func (chain *Chain) GetBlockByHash(hash []byte) (*blockchain.Block, error) {
@@ -124,81 +683,420 @@ func (chain *Chain) GetBlockByHash(hash []byte) (*blockchain.Block, error) {
 	return chain.blockStore.Get(hashHex)
 }
 
-func (chain *Chain) GetBlockByHeight(height int) (*blockchain.Block, error) {
-	if chain.Height() < height {
+func (chain *Chain) GetTransaction(hash []byte) (*blockchain.Transaction, error) {
+	return chain.txStore.Get(hex.EncodeToString(hash))
+}
+
+// ListUnspent returns the unspent outputs paying to the given address, staked
+// or not.
+func (chain *Chain) ListUnspent(address []byte) ([]*UTXO, error) {
+	utxos, err := chain.utxoStore.GetByAddress(hex.EncodeToString(address))
+	if err != nil {
+		return nil, err
+	}
+
+	unspent := []*UTXO{}
+	for _, utxo := range utxos {
+		if !utxo.Spent {
+			unspent = append(unspent, utxo)
+		}
+	}
+
+	sort.Slice(unspent, func(i, j int) bool {
+		if unspent[i].OutPoint.TxHash != unspent[j].OutPoint.TxHash {
+			return unspent[i].OutPoint.TxHash < unspent[j].OutPoint.TxHash
+		}
+		return unspent[i].OutPoint.Index < unspent[j].OutPoint.Index
+	})
+
+	return unspent, nil
+}
+
+// GetBalance returns the sum of the outputs the address can spend, its staked
+// outputs are left out.
+func (chain *Chain) GetBalance(address []byte) (int64, error) {
+	unspent, err := chain.ListUnspent(address)
+	if err != nil {
+		return 0, err
+	}
+
+	var balance int64
+	for _, utxo := range unspent {
+		if !utxo.Staked {
+			balance += utxo.Amount
+		}
+	}
+
+	return balance, nil
+}
+
+// Supply returns the sum of all unspent outputs next to the number of units the
+// monetary policy allows to exist at the current height.
+func (chain *Chain) Supply() (*Supply, error) {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	supply := &Supply{
+		Height:    chain.tip.Height,
+		Scheduled: chain.policy.IssuedAt(chain.tip.Height),
+	}
+
+	err := chain.utxoStore.ForEach(func(utxo *UTXO) error {
+		if !utxo.Spent {
+			supply.Circulating += utxo.Amount
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return supply, nil
+}
+
+func (chain *Chain) MonetaryPolicy() MonetaryPolicy {
+	return chain.policy
+}
+
+func (chain *Chain) GetHeaderByHeight(height int) (*blockchain.Header, error) {
+	if height < 0 || chain.Height() < height {
 		return nil, fmt.Errorf("given height (%d) too high - height (%d)", height, chain.Height())
 	}
 
-	header := chain.headers.Get(height)
-	hash := types.HashHeader(header)
+	return chain.headers.Get(height), nil
+}
+
+func (chain *Chain) GetBlockByHeight(height int) (*blockchain.Block, error) {
+	header, err := chain.GetHeaderByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain.GetBlockByHash(types.HashHeader(header))
+}
+
+// NewBlock assembles an unsigned block on top of the current tip. Unless the
+// coinbase address is nil, the block starts with a coinbase paying the block
+// subsidy and the fees of the given transactions to that address.
+func (chain *Chain) NewBlock(coinbaseAddress []byte, transactions []*blockchain.Transaction) (*blockchain.Block, error) {
+	height := chain.Height()
+	previousHeader := chain.headers.Get(height)
+
+	block := &blockchain.Block{
+		Header: &blockchain.Header{
+			Version:      1,
+			Height:       int32(height + 1),
+			PreviousHash: types.HashHeader(previousHeader),
+			Timestamp:    time.Now().UnixNano(),
+		},
+	}
+
+	if coinbaseAddress != nil {
+		var fees int64
+		view := newUTXOView(chain.utxoStore.Get)
+		for _, tx := range transactions {
+			fee, err := transactionFee(tx, view.Get)
+			if err != nil {
+				return nil, err
+			}
+			if err := view.apply(tx); err != nil {
+				return nil, err
+			}
+			fees += fee
+		}
+
+		coinbase := types.NewCoinbaseTransaction(coinbaseAddress, chain.policy.Subsidy(height+1)+fees, int32(height+1))
+		block.Transactions = append(block.Transactions, coinbase)
+	}
+	block.Transactions = append(block.Transactions, transactions...)
+
+	return block, nil
+}
+
+// TransactionFee returns the difference between the inputs and the outputs of
+// a transaction whose inputs are known to the chain, spent or not.
+func (chain *Chain) TransactionFee(tx *blockchain.Transaction) (int64, error) {
+	return transactionFee(tx, chain.utxoStore.Get)
+}
+
+func transactionFee(tx *blockchain.Transaction, lookup func(types.OutPoint) (*UTXO, error)) (int64, error) {
+	if types.IsCoinbase(tx) {
+		return 0, nil
+	}
+
+	var fee int64
+	for _, input := range tx.Inputs {
+		utxo, err := lookup(types.SpentOutPoint(input))
+		if err != nil {
+			return 0, err
+		}
+		fee += utxo.Amount
+	}
+
+	for _, output := range tx.Outputs {
+		fee -= output.Amount
+	}
 
-	return chain.GetBlockByHash(hash)
+	return fee, nil
+}
+
+// SelectTransactions splits the given transactions into the ones that can be
+// included in the next block, in the given order, and the ones that are
+// rejected by the current tip or by the transactions selected before them.
+func (chain *Chain) SelectTransactions(transactions []*blockchain.Transaction) ([]*blockchain.Transaction, []*blockchain.Transaction) {
+	accepted := []*blockchain.Transaction{}
+	rejected := []*blockchain.Transaction{}
+	view := newUTXOView(chain.utxoStore.Get)
+	convicted := chain.newConvictions()
+
+	for _, tx := range transactions {
+		if _, err := chain.validateSpend(tx, view.Get); err != nil {
+			rejected = append(rejected, tx)
+			continue
+		}
+		if err := convicted.check(tx, view); err != nil {
+			rejected = append(rejected, tx)
+			continue
+		}
+
+		view.apply(tx)
+		accepted = append(accepted, tx)
+	}
+
+	return accepted, rejected
 }
 
 func (chain *Chain) ValidateBlock(block *blockchain.Block) error {
-	if !types.VerifyBlock(block) {
-		return fmt.Errorf("invalid block signature")
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	return chain.validateBlock(block)
+}
+
+// validateBlock checks a block against the block tree. Its transactions are
+// only checked when it extends the tip, blocks on side branches have their
+// transactions checked once their branch gets connected.
+func (chain *Chain) validateBlock(block *blockchain.Block) error {
+	if block.GetHeader() == nil {
+		return fmt.Errorf("block has no header")
 	}
 
-	currentBlock, err := chain.GetBlockByHeight(chain.Height())
-	if err != nil {
+	if err := chain.genesis.verifyBlock(block); err != nil {
 		return err
 	}
 
-	hash := types.HashBlock(currentBlock)
-	if !bytes.Equal(hash, block.Header.PreviousHash) {
+	if _, ok := chain.index[hex.EncodeToString(types.HashBlock(block))]; ok {
+		return fmt.Errorf("block already known")
+	}
+
+	if chain.invalid.Has(hex.EncodeToString(types.HashBlock(block))) {
+		return fmt.Errorf("block is known to be invalid")
+	}
+
+	if previousHash := hex.EncodeToString(block.Header.PreviousHash); chain.invalid.Has(previousHash) {
+		chain.invalid.Add(hex.EncodeToString(types.HashBlock(block)))
+		return fmt.Errorf("block descends from the invalid block %s", previousHash)
+	}
+
+	parent, ok := chain.index[hex.EncodeToString(block.Header.PreviousHash)]
+	if !ok {
 		return fmt.Errorf("invalid previous block hash")
 	}
 
-	for _, tx := range block.Transactions {
-		if err := chain.validateTransaction(tx); err != nil {
+	if err := validateHeader(block, parent, time.Now()); err != nil {
+		return err
+	}
+
+	if !chain.descendsFromFinalized(parent) {
+		return fmt.Errorf("block conflicts with the finalized block at height %d", chain.finalized.Height)
+	}
+
+	if chain.genesis.Consensus == ConsensusWork {
+		if err := chain.checkWork(block.Header, parent); err != nil {
+			return err
+		}
+	}
+
+	if proposer := chain.proposer(parent.Height + 1); proposer != nil && !bytes.Equal(block.PublicKey, proposer.Bytes()) {
+		return fmt.Errorf("block at height %d is not signed by the scheduled proposer %x", parent.Height+1, proposer.Bytes())
+	}
+
+	// side branches are held to the stake draw too, or a cheap branch could
+	// outgrow the tip and force a reorganization
+	if err := chain.validateStaker(block, parent); err != nil {
+		return err
+	}
+
+	if parent != chain.tip {
+		return nil
+	}
+
+	return chain.validateTransactions(block.Transactions, parent.Height+1)
+}
+
+// validateTransactions checks the transactions of a block at the given height
+// against the current tip, a transaction may spend the outputs of the ones
+// before it. Only the first transaction may be a coinbase and it may not mint
+// more than the block subsidy plus the fees of the block.
+func (chain *Chain) validateTransactions(transactions []*blockchain.Transaction, height int) error {
+	view := newUTXOView(chain.utxoStore.Get)
+	convicted := chain.newConvictions()
+	var fees int64
+
+	for i, tx := range transactions {
+		if types.IsCoinbase(tx) {
+			if i != 0 {
+				return fmt.Errorf("coinbase must be the first transaction of the block")
+			}
+			continue
+		}
+
+		fee, err := chain.validateSpend(tx, view.Get)
+		if err != nil {
 			return err
 		}
+		fees += fee
+
+		if err := convicted.check(tx, view); err != nil {
+			return err
+		}
+
+		if err := view.apply(tx); err != nil {
+			return err
+		}
+	}
+
+	if len(transactions) > 0 && types.IsCoinbase(transactions[0]) {
+		return validateCoinbase(transactions[0], height, chain.policy.Subsidy(height)+fees)
+	}
+
+	return nil
+}
+
+func validateCoinbase(coinbase *blockchain.Transaction, height int, maxAmount int64) error {
+	if coinbase.Kind != blockchain.Transaction_TRANSFER {
+		return fmt.Errorf("coinbase can not be a %s transaction", coinbase.Kind)
+	}
+
+	if coinbase.Inputs[0].PreviousOutIndex != uint32(height) {
+		return fmt.Errorf("coinbase height (%d) does not match block height (%d)", coinbase.Inputs[0].PreviousOutIndex, height)
+	}
+
+	var amount int64
+	for i, output := range coinbase.Outputs {
+		if output.Amount < 0 {
+			return fmt.Errorf("coinbase output %d has a negative amount", i)
+		}
+		amount += output.Amount
+	}
+
+	if amount > maxAmount {
+		return fmt.Errorf("coinbase pays (%d) more than subsidy and fees (%d)", amount, maxAmount)
 	}
 
 	return nil
 }
 
-func (chain *Chain) validateTransaction(tx *blockchain.Transaction) error {
+// validateTransaction checks a regular transaction against the current tip and
+// returns its fee.
+func (chain *Chain) validateTransaction(tx *blockchain.Transaction) (int64, error) {
+	return chain.validateSpend(tx, chain.utxoStore.Get)
+}
+
+// validateSpend checks a regular transaction spending the outputs returned by
+// lookup and returns its fee.
+func (chain *Chain) validateSpend(tx *blockchain.Transaction, lookup func(types.OutPoint) (*UTXO, error)) (int64, error) {
+	if types.IsCoinbase(tx) {
+		return 0, fmt.Errorf("coinbase is only valid as the first transaction of a block")
+	}
+
+	if tx.Kind == blockchain.Transaction_EVIDENCE {
+		return 0, chain.validateEvidence(tx)
+	}
+	if tx.Evidence != nil {
+		return 0, fmt.Errorf("only an evidence tx can carry evidence")
+	}
+
+	// an input ties the tx to the outputs it spends, without one the same tx
+	// could be included again under the same hash
+	if len(tx.Inputs) == 0 {
+		return 0, fmt.Errorf("transaction has no inputs")
+	}
+
 	if !types.VerifyTransaction(tx) {
-		return fmt.Errorf("invalid transaction signature")
+		return 0, fmt.Errorf("invalid transaction signature")
 	}
 
-	nInputs := len(tx.Inputs)
-	sumInputs := 0
+	var sumInputs int64
+	spent := make(map[types.OutPoint]bool)
 
-	for i := 0; i < nInputs; i++ {
-		previousHash := hex.EncodeToString(tx.Inputs[i].PreviousTxHash)
-		key := fmt.Sprintf("%s_%d", previousHash, i)
-		utxo, err := chain.utxoStore.Get(key)
-		sumInputs += int(utxo.Amount)
+	for i, input := range tx.Inputs {
+		outPoint := types.SpentOutPoint(input)
+		if spent[outPoint] {
+			return 0, fmt.Errorf("input %d of tx spends %s twice", i, outPoint)
+		}
+		spent[outPoint] = true
 
+		utxo, err := lookup(outPoint)
 		if err != nil {
-			return err
+			return 0, err
 		}
+
 		if utxo.Spent {
-			return fmt.Errorf("input %d of tx %s is already spent", i, previousHash)
+			return 0, fmt.Errorf("input %d of tx %s is already spent", i, outPoint)
+		}
+
+		if unstake := tx.Kind == blockchain.Transaction_UNSTAKE; utxo.Staked != unstake {
+			if unstake {
+				return 0, fmt.Errorf("input %d of unstake tx spends %s which is not staked", i, outPoint)
+			}
+			return 0, fmt.Errorf("input %d of tx spends %s which is staked", i, outPoint)
+		}
+
+		owner := crypto.PublicKeyFromBytes(input.PublicKey).Address().String()
+		if owner != utxo.Address {
+			return 0, fmt.Errorf("input %d of tx is not signed by the owner of %s", i, outPoint)
 		}
+
+		sumInputs += utxo.Amount
 	}
 
-	sumOutputs := 0
-	for _, output := range tx.Outputs {
-		sumOutputs += int(output.Amount)
+	if tx.Kind == blockchain.Transaction_STAKE {
+		if len(tx.Outputs) == 0 {
+			return 0, fmt.Errorf("stake tx has no outputs")
+		}
+		for i, output := range tx.Outputs {
+			if _, ok := chain.Slashed(hex.EncodeToString(output.Address)); ok {
+				return 0, fmt.Errorf("output %d of stake tx pays to a slashed validator", i)
+			}
+		}
+	}
+
+	var sumOutputs int64
+	for i, output := range tx.Outputs {
+		if output.Amount < 0 {
+			return 0, fmt.Errorf("output %d has a negative amount", i)
+		}
+		sumOutputs += output.Amount
 	}
 
 	if sumInputs < sumOutputs {
-		return fmt.Errorf("insufficient balance got (%d) spending (%d)", sumInputs, sumOutputs)
+		return 0, fmt.Errorf("insufficient balance got (%d) spending (%d)", sumInputs, sumOutputs)
 	}
 
-	return nil
+	return sumInputs - sumOutputs, nil
 }
 
-func createGenesisBlock() *blockchain.Block {
+// createGenesisBlock returns the genesis block of the network, its nonce
+// carries the first bytes of the commitment to the consensus parameters and
+// the monetary policy.
+func createGenesisBlock(genesis Genesis, policy MonetaryPolicy) *blockchain.Block {
 	privateKey := crypto.NewPrivateKeyFromString(seed)
 
 	block := &blockchain.Block{
 		Header: &blockchain.Header{
 			Version: 1,
+			Nonce:   binary.BigEndian.Uint64(genesis.commitment(policy)),
 		},
 	}
 
@@ -207,7 +1105,7 @@ func createGenesisBlock() *blockchain.Block {
 		Inputs:  []*blockchain.TxInput{},
 		Outputs: []*blockchain.TxOutput{
 			{
-				Amount:  1000,
+				Amount:  genesisAmount,
 				Address: privateKey.Public().Address().Bytes(),
 			},
 		},
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/hex"
 	"testing"
 
 	"github.com/blockchain/crypto"
@@ -19,6 +20,7 @@ func randomBlock(t *testing.T, chain *Chain) *blockchain.Block {
 	require.Nil(t, err)
 
 	block.Header.PreviousHash = types.HashBlock(previousBlock)
+	block.Header.Height = previousBlock.Header.Height + 1
 
 	types.SignBlock(privateKey, block)
 	return block
@@ -137,3 +139,214 @@ func TestAddBlockWithTx(t *testing.T) {
 	types.SignBlock(privateKey, block)
 	require.Nil(t, chain.AddBlock(block))
 }
+
+func TestOpenChainRestoresHeaders(t *testing.T) {
+	config := DiskStoreConfig{Dir: t.TempDir()}
+
+	store, err := OpenDiskStore(config)
+	require.Nil(t, err)
+
+	chain, err := OpenChain(store.Blocks, store.Transactions, store.UTXOs)
+	require.Nil(t, err)
+
+	for i := 1; i < 10; i++ {
+		require.Nil(t, chain.AddBlock(randomBlock(t, chain)))
+	}
+	tip, err := chain.GetBlockByHeight(chain.Height())
+	require.Nil(t, err)
+	require.Nil(t, store.Close())
+
+	store, err = OpenDiskStore(config)
+	require.Nil(t, err)
+	defer store.Close()
+
+	chain, err = OpenChain(store.Blocks, store.Transactions, store.UTXOs)
+	require.Nil(t, err)
+	require.Equal(t, 9, chain.Height())
+
+	fetchedTip, err := chain.GetBlockByHeight(chain.Height())
+	require.Nil(t, err)
+	require.Equal(t, types.HashBlock(tip), types.HashBlock(fetchedTip))
+
+	require.Nil(t, chain.AddBlock(randomBlock(t, chain)))
+	require.Equal(t, 10, chain.Height())
+}
+
+func TestOpenChainDetectsCorruption(t *testing.T) {
+	blockStore := NewMemoryBlockStore()
+	chain, err := OpenChain(blockStore, NewMemoryTxStore(), NewMemoryUTXOStore())
+	require.Nil(t, err)
+
+	block := util.RandomBlock()
+	types.SignBlock(crypto.GeneratePrivateKey(), block)
+	require.Nil(t, blockStore.Put(block))
+	require.Nil(t, blockStore.SetTip(hex.EncodeToString(types.HashBlock(block))))
+
+	_, err = OpenChain(blockStore, chain.txStore, chain.utxoStore)
+	var corruption *ChainCorruptionError
+	require.ErrorAs(t, err, &corruption)
+	require.Equal(t, hex.EncodeToString(block.Header.PreviousHash), corruption.Hash)
+}
+
+func TestOpenChainChecksHeaderRules(t *testing.T) {
+	blockStore := NewMemoryBlockStore()
+	chain, err := OpenChain(blockStore, NewMemoryTxStore(), NewMemoryUTXOStore())
+	require.Nil(t, err)
+
+	block := randomBlock(t, chain)
+	block.Header.Version = 2
+	types.SignBlock(crypto.GeneratePrivateKey(), block)
+	require.Nil(t, blockStore.Put(block))
+	require.Nil(t, blockStore.SetTip(hex.EncodeToString(types.HashBlock(block))))
+
+	_, err = OpenChain(blockStore, chain.txStore, chain.utxoStore)
+	var corruption *ChainCorruptionError
+	require.ErrorAs(t, err, &corruption)
+	require.Equal(t, hex.EncodeToString(types.HashBlock(block)), corruption.Hash)
+	require.Contains(t, corruption.Reason, RuleVersion.String())
+}
+
+func TestDisconnectTip(t *testing.T) {
+	config := DiskStoreConfig{Dir: t.TempDir()}
+
+	store, err := OpenDiskStore(config)
+	require.Nil(t, err)
+
+	chain, err := OpenChain(store.Blocks, store.Transactions, store.UTXOs)
+	require.Nil(t, err)
+
+	var (
+		privateKey = crypto.NewPrivateKeyFromString(seed)
+		tx         = genesisSpend(t, chain, 100)
+		txHash     = hex.EncodeToString(types.HashTransaction(tx))
+		block      = randomBlock(t, chain)
+	)
+	genesis, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+	genesisOutput := types.NewOutPoint(types.HashTransaction(genesis.Transactions[0]), 0)
+
+	block.Transactions = append(block.Transactions, tx)
+	types.SignBlock(privateKey, block)
+	require.Nil(t, chain.AddBlock(block))
+	require.Nil(t, store.Close())
+
+	store, err = OpenDiskStore(config)
+	require.Nil(t, err)
+	defer store.Close()
+
+	chain, err = OpenChain(store.Blocks, store.Transactions, store.UTXOs)
+	require.Nil(t, err)
+	require.Equal(t, 1, chain.Height())
+
+	require.Nil(t, chain.DisconnectTip())
+	require.Equal(t, 0, chain.Height())
+
+	utxo, err := chain.utxoStore.Get(genesisOutput)
+	require.Nil(t, err)
+	require.False(t, utxo.Spent)
+
+	_, err = chain.utxoStore.Get(types.NewOutPoint(types.HashTransaction(tx), 0))
+	require.NotNil(t, err)
+	_, err = chain.txStore.Get(txHash)
+	require.NotNil(t, err)
+
+	require.NotNil(t, chain.DisconnectTip())
+
+	tip, err := store.Blocks.Tip()
+	require.Nil(t, err)
+	require.Equal(t, hex.EncodeToString(types.HashBlock(genesis)), tip)
+}
+
+func TestConnectBlockSyncsStores(t *testing.T) {
+	store, err := OpenDiskStore(DiskStoreConfig{Dir: t.TempDir(), Sync: SyncNever})
+	require.Nil(t, err)
+	defer store.Close()
+
+	chain, err := OpenChain(store.Blocks, store.Transactions, store.UTXOs)
+	require.Nil(t, err)
+
+	block := randomBlock(t, chain)
+	block.Transactions = append(block.Transactions, genesisSpend(t, chain, 100))
+	types.SignBlock(crypto.NewPrivateKeyFromString(seed), block)
+
+	// the outputs of a block are on the disk before the tip moves to it
+	require.Nil(t, chain.AddBlock(block))
+	require.False(t, store.UTXOs.log.dirty)
+	require.False(t, store.Transactions.log.dirty)
+
+	require.Nil(t, chain.DisconnectTip())
+	require.False(t, store.UTXOs.log.dirty)
+	require.False(t, store.Transactions.log.dirty)
+}
+
+func TestOpenChainRecoversInterruptedBlock(t *testing.T) {
+	config := DiskStoreConfig{Dir: t.TempDir()}
+
+	store, err := OpenDiskStore(config)
+	require.Nil(t, err)
+
+	chain, err := OpenChain(store.Blocks, store.Transactions, store.UTXOs)
+	require.Nil(t, err)
+
+	var (
+		privateKey = crypto.NewPrivateKeyFromString(seed)
+		tx         = genesisSpend(t, chain, 100)
+		block      = randomBlock(t, chain)
+	)
+	genesis, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+	genesisOutput := types.NewOutPoint(types.HashTransaction(genesis.Transactions[0]), 0)
+
+	block.Transactions = append(block.Transactions, tx)
+	types.SignBlock(privateKey, block)
+	hash := hex.EncodeToString(types.HashBlock(block))
+
+	// the node stops after writing the outputs of the block, before moving
+	// the tip
+	undo, err := chain.blockUndo(block)
+	require.Nil(t, err)
+	require.Nil(t, store.Blocks.Put(block))
+	require.Nil(t, store.Blocks.PutUndo(hash, undo))
+	require.Nil(t, store.Blocks.SetPending(hash))
+	require.Nil(t, chain.applyBlock(block, undo))
+	require.Nil(t, store.Close())
+
+	store, err = OpenDiskStore(config)
+	require.Nil(t, err)
+
+	chain, err = OpenChain(store.Blocks, store.Transactions, store.UTXOs)
+	require.Nil(t, err)
+	require.Equal(t, 0, chain.Height())
+
+	utxo, err := chain.utxoStore.Get(genesisOutput)
+	require.Nil(t, err)
+	require.False(t, utxo.Spent)
+	_, err = chain.utxoStore.Get(types.NewOutPoint(types.HashTransaction(tx), 0))
+	require.NotNil(t, err)
+
+	// the node stops while disconnecting the block, after restoring the
+	// outputs it spent
+	require.Nil(t, chain.AddBlock(block))
+	require.Nil(t, store.Blocks.SetPending(hash))
+	require.Nil(t, chain.utxoStore.Put(utxo))
+	require.Nil(t, store.Close())
+
+	store, err = OpenDiskStore(config)
+	require.Nil(t, err)
+	defer store.Close()
+
+	chain, err = OpenChain(store.Blocks, store.Transactions, store.UTXOs)
+	require.Nil(t, err)
+	require.Equal(t, 0, chain.Height())
+
+	utxo, err = chain.utxoStore.Get(genesisOutput)
+	require.Nil(t, err)
+	require.False(t, utxo.Spent)
+	_, err = chain.txStore.Get(hex.EncodeToString(types.HashTransaction(tx)))
+	require.NotNil(t, err)
+
+	pending, err := store.Blocks.Pending()
+	require.Nil(t, err)
+	require.Equal(t, "", pending)
+	require.Nil(t, chain.AddBlock(block))
+}
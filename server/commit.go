@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+)
+
+// hasQuorum reports whether votes is more than two thirds of the validators.
+func hasQuorum(votes, validators int) bool {
+	return 3*votes > 2*validators
+}
+
+// verifyCommit checks that the commit holds matching precommits signed by
+// more than two thirds of the validators at its height.
+func verifyCommit(commit *blockchain.Commit, validators []*crypto.PublicKey) error {
+	if len(validators) == 0 {
+		return fmt.Errorf("chain has no validator set")
+	}
+
+	signed := make(map[string]struct{})
+	for _, vote := range commit.Precommits {
+		if vote.Type != blockchain.Vote_PRECOMMIT || vote.Height != commit.Height || vote.Round != commit.Round || !bytes.Equal(vote.BlockHash, commit.BlockHash) {
+			return fmt.Errorf("precommit does not match the commit")
+		}
+		if !isValidator(validators, vote.PublicKey) {
+			return fmt.Errorf("precommit from unknown validator %x", vote.PublicKey)
+		}
+		if !types.VerifyVote(vote) {
+			return fmt.Errorf("invalid precommit signature from validator %x", vote.PublicKey)
+		}
+		signed[string(vote.PublicKey)] = struct{}{}
+	}
+
+	if !hasQuorum(len(signed), len(validators)) {
+		return fmt.Errorf("commit is signed by %d of %d validators", len(signed), len(validators))
+	}
+
+	return nil
+}
+
+// Finalize checks the commit against the validator set, stores it and makes
+// its block the finalized block, reorganizing the main chain onto it when
+// needed. The finalized block and its ancestors are never disconnected again.
+func (chain *Chain) Finalize(commit *blockchain.Commit) error {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	if err := verifyCommit(commit, chain.validators(int(commit.Height))); err != nil {
+		return err
+	}
+
+	hash := hex.EncodeToString(commit.BlockHash)
+	node, ok := chain.index[hash]
+	if !ok {
+		return fmt.Errorf("unknown block [%s]", hash)
+	}
+	if node.Height != int(commit.Height) {
+		return fmt.Errorf("commit height %d does not match block height %d", commit.Height, node.Height)
+	}
+
+	if node.Height <= chain.finalized.Height {
+		ancestor := chain.finalized
+		for ancestor.Height > node.Height {
+			ancestor = ancestor.Parent
+		}
+		if ancestor != node {
+			return fmt.Errorf("commit conflicts with the finalized block at height %d", chain.finalized.Height)
+		}
+		return nil
+	}
+
+	if !chain.descendsFromFinalized(node) {
+		return fmt.Errorf("commit conflicts with the finalized block at height %d", chain.finalized.Height)
+	}
+
+	if findFork(chain.tip, node) != node {
+		if err := chain.reorganize(node); err != nil {
+			return err
+		}
+	}
+
+	if err := chain.blockStore.PutCommit(hash, commit); err != nil {
+		return err
+	}
+	chain.finalized = node
+
+	return nil
+}
+
+// Finalized returns the last finalized block, the genesis block until a
+// commit is known.
+func (chain *Chain) Finalized() *BlockNode {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	return chain.finalized
+}
+
+// GetCommit returns the commit finalizing the block with the given hash, nil
+// when the block has not been finalized by its own commit.
+func (chain *Chain) GetCommit(hash []byte) (*blockchain.Commit, error) {
+	return chain.blockStore.GetCommit(hex.EncodeToString(hash))
+}
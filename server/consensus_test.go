@@ -0,0 +1,274 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/stretchr/testify/require"
+)
+
+type spend struct {
+	key      *crypto.PrivateKey
+	outPoint types.OutPoint
+}
+
+func payTo(key *crypto.PrivateKey, amount int64) *blockchain.TxOutput {
+	return &blockchain.TxOutput{
+		Amount:  amount,
+		Address: key.Public().Address().Bytes(),
+	}
+}
+
+func signedTx(spends []spend, outputs ...*blockchain.TxOutput) *blockchain.Transaction {
+	return signedKindTx(blockchain.Transaction_TRANSFER, spends, outputs...)
+}
+
+func signedKindTx(kind blockchain.Transaction_Kind, spends []spend, outputs ...*blockchain.TxOutput) *blockchain.Transaction {
+	tx := &blockchain.Transaction{
+		Version: 1,
+		Outputs: outputs,
+		Kind:    kind,
+	}
+	for _, s := range spends {
+		tx.Inputs = append(tx.Inputs, &blockchain.TxInput{
+			PreviousTxHash:   s.outPoint.Hash(),
+			PreviousOutIndex: s.outPoint.Index,
+			PublicKey:        s.key.Public().Bytes(),
+		})
+	}
+	for i, s := range spends {
+		tx.Inputs[i].Signature = types.SignTransaction(s.key, tx).Bytes()
+	}
+
+	return tx
+}
+
+func mineBlock(chain *Chain, transactions ...*blockchain.Transaction) error {
+	block, err := chain.NewBlock(nil, transactions)
+	if err != nil {
+		return err
+	}
+	types.SignBlock(crypto.GeneratePrivateKey(), block)
+
+	return chain.AddBlock(block)
+}
+
+func outPoint(tx *blockchain.Transaction, index uint32) types.OutPoint {
+	return types.NewOutPoint(types.HashTransaction(tx), index)
+}
+
+func balance(t *testing.T, chain *Chain, key *crypto.PrivateKey) int64 {
+	balance, err := chain.GetBalance(key.Public().Address().Bytes())
+	require.Nil(t, err)
+
+	return balance
+}
+
+type consensusFixture struct {
+	chain      *Chain
+	genesisKey *crypto.PrivateKey
+	alice      *crypto.PrivateKey
+	bob        *crypto.PrivateKey
+	split      *blockchain.Transaction
+}
+
+// newConsensusFixture splits the genesis output into 300 for alice at index 0
+// and 700 for bob at index 1.
+func newConsensusFixture(t *testing.T, opts ...ChainOption) *consensusFixture {
+	fixture := &consensusFixture{
+		chain:      NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), opts...),
+		genesisKey: crypto.NewPrivateKeyFromString(seed),
+		alice:      crypto.GeneratePrivateKey(),
+		bob:        crypto.GeneratePrivateKey(),
+	}
+
+	genesis, err := fixture.chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	fixture.split = signedTx(
+		[]spend{{fixture.genesisKey, outPoint(genesis.Transactions[0], 0)}},
+		payTo(fixture.alice, 300),
+		payTo(fixture.bob, 700),
+	)
+	require.Nil(t, mineBlock(fixture.chain, fixture.split))
+
+	return fixture
+}
+
+func TestConsensusMultiOutput(t *testing.T) {
+	fixture := newConsensusFixture(t)
+
+	require.Equal(t, int64(0), balance(t, fixture.chain, fixture.genesisKey))
+	require.Equal(t, int64(300), balance(t, fixture.chain, fixture.alice))
+	require.Equal(t, int64(700), balance(t, fixture.chain, fixture.bob))
+}
+
+func TestConsensusCrossIndexSpend(t *testing.T) {
+	var (
+		fixture = newConsensusFixture(t)
+		carol   = crypto.GeneratePrivateKey()
+	)
+
+	// input 0 spends output 1
+	tx := signedTx([]spend{{fixture.bob, outPoint(fixture.split, 1)}}, payTo(carol, 700))
+	require.Nil(t, mineBlock(fixture.chain, tx))
+
+	spent, err := fixture.chain.utxoStore.Get(outPoint(fixture.split, 1))
+	require.Nil(t, err)
+	require.True(t, spent.Spent)
+
+	unspent, err := fixture.chain.utxoStore.Get(outPoint(fixture.split, 0))
+	require.Nil(t, err)
+	require.False(t, unspent.Spent)
+
+	require.Equal(t, int64(300), balance(t, fixture.chain, fixture.alice))
+	require.Equal(t, int64(0), balance(t, fixture.chain, fixture.bob))
+	require.Equal(t, int64(700), balance(t, fixture.chain, carol))
+
+	again := signedTx([]spend{{fixture.bob, outPoint(fixture.split, 1)}}, payTo(fixture.bob, 700))
+	require.NotNil(t, mineBlock(fixture.chain, again))
+}
+
+func TestConsensusMultiInput(t *testing.T) {
+	var (
+		fixture = newConsensusFixture(t)
+		carol   = crypto.GeneratePrivateKey()
+	)
+
+	tx := signedTx(
+		[]spend{
+			{fixture.bob, outPoint(fixture.split, 1)},
+			{fixture.alice, outPoint(fixture.split, 0)},
+		},
+		payTo(carol, 950),
+		payTo(fixture.alice, 50),
+	)
+	require.Nil(t, mineBlock(fixture.chain, tx))
+
+	require.Equal(t, int64(50), balance(t, fixture.chain, fixture.alice))
+	require.Equal(t, int64(0), balance(t, fixture.chain, fixture.bob))
+	require.Equal(t, int64(950), balance(t, fixture.chain, carol))
+
+	require.Nil(t, fixture.chain.DisconnectTip())
+	require.Equal(t, int64(300), balance(t, fixture.chain, fixture.alice))
+	require.Equal(t, int64(700), balance(t, fixture.chain, fixture.bob))
+	require.Equal(t, int64(0), balance(t, fixture.chain, carol))
+}
+
+func TestConsensusSpendWithinBlock(t *testing.T) {
+	var (
+		fixture = newConsensusFixture(t)
+		carol   = crypto.GeneratePrivateKey()
+		parent  = signedTx([]spend{{fixture.alice, outPoint(fixture.split, 0)}}, payTo(fixture.alice, 290))
+		child   = signedTx([]spend{{fixture.alice, outPoint(parent, 0)}}, payTo(carol, 280))
+	)
+
+	require.NotNil(t, mineBlock(fixture.chain, child, parent))
+	require.Nil(t, mineBlock(fixture.chain, parent, child))
+	require.Equal(t, int64(0), balance(t, fixture.chain, fixture.alice))
+	require.Equal(t, int64(280), balance(t, fixture.chain, carol))
+
+	require.Nil(t, fixture.chain.DisconnectTip())
+	require.Equal(t, int64(300), balance(t, fixture.chain, fixture.alice))
+	require.Equal(t, int64(0), balance(t, fixture.chain, carol))
+
+	_, err := fixture.chain.utxoStore.Get(outPoint(parent, 0))
+	require.NotNil(t, err)
+}
+
+func TestConsensusRejectsInvalidSpends(t *testing.T) {
+	var (
+		fixture = newConsensusFixture(t)
+		carol   = crypto.GeneratePrivateKey()
+		alice0  = outPoint(fixture.split, 0)
+		bob1    = outPoint(fixture.split, 1)
+	)
+
+	cases := map[string][]*blockchain.Transaction{
+		"missing output index": {
+			signedTx([]spend{{fixture.alice, outPoint(fixture.split, 2)}}, payTo(carol, 1)),
+		},
+		"spent by non owner": {
+			signedTx([]spend{{fixture.alice, bob1}}, payTo(carol, 700)),
+		},
+		"same input twice": {
+			signedTx([]spend{{fixture.alice, alice0}, {fixture.alice, alice0}}, payTo(carol, 600)),
+		},
+		"overspend": {
+			signedTx([]spend{{fixture.alice, alice0}}, payTo(carol, 301)),
+		},
+		"negative output": {
+			signedTx([]spend{{fixture.alice, alice0}}, payTo(carol, 400), payTo(fixture.alice, -100)),
+		},
+		"no inputs": {
+			{Version: 1, Outputs: []*blockchain.TxOutput{payTo(carol, 0)}},
+			{Version: 1, Outputs: []*blockchain.TxOutput{payTo(carol, 0)}},
+		},
+		"double spend in block": {
+			signedTx([]spend{{fixture.alice, alice0}}, payTo(carol, 300)),
+			signedTx([]spend{{fixture.alice, alice0}}, payTo(fixture.bob, 300)),
+		},
+	}
+
+	for name, transactions := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.NotNil(t, mineBlock(fixture.chain, transactions...))
+			require.Equal(t, 1, fixture.chain.Height())
+		})
+	}
+
+	require.Equal(t, int64(300), balance(t, fixture.chain, fixture.alice))
+	require.Equal(t, int64(700), balance(t, fixture.chain, fixture.bob))
+}
+
+func TestConsensusCoinbase(t *testing.T) {
+	var (
+		fixture   = newConsensusFixture(t)
+		validator = crypto.GeneratePrivateKey()
+		address   = validator.Public().Address().Bytes()
+		tx        = signedTx([]spend{{fixture.alice, outPoint(fixture.split, 0)}}, payTo(fixture.bob, 290))
+	)
+
+	cases := map[string][]*blockchain.Transaction{
+		"coinbase pays more than subsidy and fees": {
+			types.NewCoinbaseTransaction(address, DefaultMonetaryPolicy.InitialSubsidy+11, 2),
+			tx,
+		},
+		"coinbase is not first": {
+			tx,
+			types.NewCoinbaseTransaction(address, DefaultMonetaryPolicy.InitialSubsidy, 2),
+		},
+		"coinbase height does not match": {
+			types.NewCoinbaseTransaction(address, DefaultMonetaryPolicy.InitialSubsidy, 3),
+		},
+		"two coinbases": {
+			types.NewCoinbaseTransaction(address, DefaultMonetaryPolicy.InitialSubsidy, 2),
+			types.NewCoinbaseTransaction(address, 0, 2),
+		},
+	}
+
+	for name, transactions := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.NotNil(t, mineBlock(fixture.chain, transactions...))
+			require.Equal(t, 1, fixture.chain.Height())
+		})
+	}
+
+	fee, err := fixture.chain.TransactionFee(tx)
+	require.Nil(t, err)
+	require.Equal(t, int64(10), fee)
+
+	block, err := fixture.chain.NewBlock(address, []*blockchain.Transaction{tx})
+	require.Nil(t, err)
+	types.SignBlock(validator, block)
+	require.Nil(t, fixture.chain.AddBlock(block))
+
+	require.Equal(t, int64(DefaultMonetaryPolicy.InitialSubsidy+10), balance(t, fixture.chain, validator))
+
+	// coinbase outputs are spendable
+	reward := signedTx([]spend{{validator, outPoint(block.Transactions[0], 0)}}, payTo(fixture.alice, DefaultMonetaryPolicy.InitialSubsidy))
+	require.Nil(t, mineBlock(fixture.chain, reward))
+	require.Equal(t, int64(0), balance(t, fixture.chain, validator))
+}
@@ -0,0 +1,386 @@
+package server
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// These keys can never collide with a block hash, which is always hex encoded.
+const (
+	tipKey       = "tip"
+	pendingKey   = "pending"
+	undoPrefix   = "undo_"
+	commitPrefix = "commit_"
+)
+
+func (config DiskStoreConfig) sub(name string) DiskStoreConfig {
+	config.Dir = filepath.Join(config.Dir, name)
+	return config
+}
+
+type DiskUTXOStore struct {
+	log *segmentLog
+
+	lock      sync.RWMutex
+	addresses addressIndex
+}
+
+func NewDiskUTXOStore(config DiskStoreConfig) (*DiskUTXOStore, error) {
+	log, err := openSegmentLog(config.sub("utxos"))
+	if err != nil {
+		return nil, err
+	}
+
+	store := &DiskUTXOStore{
+		log:       log,
+		addresses: make(addressIndex),
+	}
+
+	err = log.ForEach(func(key string, value []byte) error {
+		utxo := &UTXO{}
+		if err := json.Unmarshal(value, utxo); err != nil {
+			return err
+		}
+
+		store.addresses.add(utxo.Address, utxo.OutPoint)
+		return nil
+	})
+	if err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (store *DiskUTXOStore) Get(outPoint types.OutPoint) (*UTXO, error) {
+	b, err := store.log.Get(outPoint.String())
+	if err == errNotFound {
+		return nil, fmt.Errorf("could not find utxo %s", outPoint)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	utxo := &UTXO{}
+	if err := json.Unmarshal(b, utxo); err != nil {
+		return nil, err
+	}
+
+	return utxo, nil
+}
+
+func (store *DiskUTXOStore) Put(utxo *UTXO) error {
+	b, err := json.Marshal(utxo)
+	if err != nil {
+		return err
+	}
+
+	if err := store.log.Put(utxo.OutPoint.String(), b); err != nil {
+		return err
+	}
+
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.addresses.add(utxo.Address, utxo.OutPoint)
+	return nil
+}
+
+func (store *DiskUTXOStore) Delete(outPoint types.OutPoint) error {
+	b, err := store.log.Get(outPoint.String())
+	if err == errNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	utxo := &UTXO{}
+	if err := json.Unmarshal(b, utxo); err != nil {
+		return err
+	}
+
+	if err := store.log.Delete(outPoint.String()); err != nil {
+		return err
+	}
+
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.addresses.remove(utxo.Address, outPoint)
+	return nil
+}
+
+func (store *DiskUTXOStore) GetByAddress(address string) ([]*UTXO, error) {
+	store.lock.RLock()
+	outPoints := make([]types.OutPoint, 0, len(store.addresses[address]))
+	for outPoint := range store.addresses[address] {
+		outPoints = append(outPoints, outPoint)
+	}
+	store.lock.RUnlock()
+
+	utxos := make([]*UTXO, 0, len(outPoints))
+	for _, outPoint := range outPoints {
+		utxo, err := store.Get(outPoint)
+		if err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, utxo)
+	}
+
+	return utxos, nil
+}
+
+func (store *DiskUTXOStore) ForEach(fn func(utxo *UTXO) error) error {
+	return store.log.ForEach(func(key string, value []byte) error {
+		utxo := &UTXO{}
+		if err := json.Unmarshal(value, utxo); err != nil {
+			return err
+		}
+
+		return fn(utxo)
+	})
+}
+
+func (store *DiskUTXOStore) Sync() error {
+	return store.log.Sync()
+}
+
+func (store *DiskUTXOStore) Close() error {
+	return store.log.Close()
+}
+
+type DiskTxStore struct {
+	log *segmentLog
+}
+
+func NewDiskTxStore(config DiskStoreConfig) (*DiskTxStore, error) {
+	log, err := openSegmentLog(config.sub("transactions"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiskTxStore{log: log}, nil
+}
+
+func (store *DiskTxStore) Put(tx *blockchain.Transaction) error {
+	b, err := proto.Marshal(tx)
+	if err != nil {
+		return err
+	}
+
+	hash := hex.EncodeToString(types.HashTransaction(tx))
+	return store.log.Put(hash, b)
+}
+
+func (store *DiskTxStore) Get(hash string) (*blockchain.Transaction, error) {
+	b, err := store.log.Get(hash)
+	if err == errNotFound {
+		return nil, fmt.Errorf("could not find transaction with hash %s", hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &blockchain.Transaction{}
+	if err := proto.Unmarshal(b, tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (store *DiskTxStore) Delete(hash string) error {
+	return store.log.Delete(hash)
+}
+
+func (store *DiskTxStore) Sync() error {
+	return store.log.Sync()
+}
+
+func (store *DiskTxStore) Close() error {
+	return store.log.Close()
+}
+
+type DiskBlockStore struct {
+	log *segmentLog
+}
+
+func NewDiskBlockStore(config DiskStoreConfig) (*DiskBlockStore, error) {
+	log, err := openSegmentLog(config.sub("blocks"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiskBlockStore{log: log}, nil
+}
+
+func (store *DiskBlockStore) Put(block *blockchain.Block) error {
+	b, err := proto.Marshal(block)
+	if err != nil {
+		return err
+	}
+
+	hash := hex.EncodeToString(types.HashBlock(block))
+	return store.log.Put(hash, b)
+}
+
+func (store *DiskBlockStore) Get(hash string) (*blockchain.Block, error) {
+	b, err := store.log.Get(hash)
+	if err == errNotFound {
+		return nil, fmt.Errorf("block with hash [%s] does not exists", hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	block := &blockchain.Block{}
+	if err := proto.Unmarshal(b, block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+func (store *DiskBlockStore) Tip() (string, error) {
+	b, err := store.log.Get(tipKey)
+	if err == errNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (store *DiskBlockStore) SetTip(hash string) error {
+	return store.log.Put(tipKey, []byte(hash))
+}
+
+func (store *DiskBlockStore) Pending() (string, error) {
+	b, err := store.log.Get(pendingKey)
+	if err == errNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (store *DiskBlockStore) SetPending(hash string) error {
+	return store.log.Put(pendingKey, []byte(hash))
+}
+
+func (store *DiskBlockStore) PutUndo(hash string, undo *BlockUndo) error {
+	b, err := json.Marshal(undo)
+	if err != nil {
+		return err
+	}
+
+	return store.log.Put(undoPrefix+hash, b)
+}
+
+func (store *DiskBlockStore) GetUndo(hash string) (*BlockUndo, error) {
+	b, err := store.log.Get(undoPrefix + hash)
+	if err == errNotFound {
+		return nil, fmt.Errorf("undo data for block [%s] does not exists", hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	undo := &BlockUndo{}
+	if err := json.Unmarshal(b, undo); err != nil {
+		return nil, err
+	}
+
+	return undo, nil
+}
+
+func (store *DiskBlockStore) PutCommit(hash string, commit *blockchain.Commit) error {
+	b, err := proto.Marshal(commit)
+	if err != nil {
+		return err
+	}
+
+	return store.log.Put(commitPrefix+hash, b)
+}
+
+func (store *DiskBlockStore) GetCommit(hash string) (*blockchain.Commit, error) {
+	b, err := store.log.Get(commitPrefix + hash)
+	if err == errNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	commit := &blockchain.Commit{}
+	if err := proto.Unmarshal(b, commit); err != nil {
+		return nil, err
+	}
+
+	return commit, nil
+}
+
+func (store *DiskBlockStore) Sync() error {
+	return store.log.Sync()
+}
+
+func (store *DiskBlockStore) Close() error {
+	return store.log.Close()
+}
+
+type DiskStore struct {
+	Blocks       *DiskBlockStore
+	Transactions *DiskTxStore
+	UTXOs        *DiskUTXOStore
+}
+
+func OpenDiskStore(config DiskStoreConfig) (*DiskStore, error) {
+	blocks, err := NewDiskBlockStore(config)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := NewDiskTxStore(config)
+	if err != nil {
+		blocks.Close()
+		return nil, err
+	}
+
+	utxos, err := NewDiskUTXOStore(config)
+	if err != nil {
+		blocks.Close()
+		transactions.Close()
+		return nil, err
+	}
+
+	return &DiskStore{
+		Blocks:       blocks,
+		Transactions: transactions,
+		UTXOs:        utxos,
+	}, nil
+}
+
+func (store *DiskStore) Close() error {
+	var err error
+	for _, closer := range []func() error{store.Blocks.Close, store.Transactions.Close, store.UTXOs.Close} {
+		if closeErr := closer(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/blockchain/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskBlockStorePersists(t *testing.T) {
+	config := DiskStoreConfig{Dir: t.TempDir()}
+
+	store, err := NewDiskBlockStore(config)
+	require.Nil(t, err)
+
+	block := util.RandomBlock()
+	types.SignBlock(crypto.GeneratePrivateKey(), block)
+	hash := hex.EncodeToString(types.HashBlock(block))
+	require.Nil(t, store.Put(block))
+	require.Nil(t, store.Close())
+
+	store, err = NewDiskBlockStore(config)
+	require.Nil(t, err)
+	defer store.Close()
+
+	fetchedBlock, err := store.Get(hash)
+	require.Nil(t, err)
+	require.Equal(t, types.HashBlock(block), types.HashBlock(fetchedBlock))
+	require.Equal(t, block.Signature, fetchedBlock.Signature)
+
+	_, err = store.Get(hex.EncodeToString(util.RandomHash()))
+	require.NotNil(t, err)
+}
+
+func TestDiskTxStorePersists(t *testing.T) {
+	config := DiskStoreConfig{Dir: t.TempDir(), Sync: SyncNever}
+
+	store, err := NewDiskTxStore(config)
+	require.Nil(t, err)
+
+	tx := &blockchain.Transaction{
+		Version: 1,
+		Outputs: []*blockchain.TxOutput{{Amount: 10, Address: util.RandomHash()[:20]}},
+	}
+	hash := hex.EncodeToString(types.HashTransaction(tx))
+	require.Nil(t, store.Put(tx))
+	require.Nil(t, store.Close())
+
+	store, err = NewDiskTxStore(config)
+	require.Nil(t, err)
+	defer store.Close()
+
+	fetchedTx, err := store.Get(hash)
+	require.Nil(t, err)
+	require.Equal(t, types.HashTransaction(tx), types.HashTransaction(fetchedTx))
+}
+
+func TestDiskUTXOStoreOverwrite(t *testing.T) {
+	config := DiskStoreConfig{Dir: t.TempDir(), Sync: SyncInterval}
+
+	store, err := NewDiskUTXOStore(config)
+	require.Nil(t, err)
+
+	utxo := &UTXO{OutPoint: types.NewOutPoint(util.RandomHash(), 1), Amount: 50}
+	require.Nil(t, store.Put(utxo))
+
+	utxo.Spent = true
+	require.Nil(t, store.Put(utxo))
+	require.Nil(t, store.Close())
+
+	store, err = NewDiskUTXOStore(config)
+	require.Nil(t, err)
+	defer store.Close()
+
+	fetchedUTXO, err := store.Get(utxo.OutPoint)
+	require.Nil(t, err)
+	require.Equal(t, utxo, fetchedUTXO)
+}
+
+func TestDiskUTXOStoreAddressIndex(t *testing.T) {
+	var (
+		config  = DiskStoreConfig{Dir: t.TempDir()}
+		address = hex.EncodeToString(util.RandomHash()[:20])
+		hash    = util.RandomHash()
+	)
+
+	store, err := NewDiskUTXOStore(config)
+	require.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.Nil(t, store.Put(&UTXO{OutPoint: types.NewOutPoint(hash, uint32(i)), Amount: 10, Address: address}))
+	}
+	require.Nil(t, store.Put(&UTXO{OutPoint: types.NewOutPoint(hash, 3), Amount: 10, Address: hex.EncodeToString(util.RandomHash()[:20])}))
+	require.Nil(t, store.Delete(types.NewOutPoint(hash, 0)))
+	require.Nil(t, store.Close())
+
+	store, err = NewDiskUTXOStore(config)
+	require.Nil(t, err)
+	defer store.Close()
+
+	utxos, err := store.GetByAddress(address)
+	require.Nil(t, err)
+	require.Len(t, utxos, 2)
+	for _, utxo := range utxos {
+		require.Equal(t, address, utxo.Address)
+	}
+}
+
+func TestSegmentLogRollsSegments(t *testing.T) {
+	config := DiskStoreConfig{Dir: t.TempDir(), MaxSegmentSize: 128}
+
+	segLog, err := openSegmentLog(config)
+	require.Nil(t, err)
+
+	for i := 0; i < 20; i++ {
+		require.Nil(t, segLog.Put(hex.EncodeToString([]byte{byte(i)}), util.RandomHash()))
+	}
+	require.Nil(t, segLog.Delete("00"))
+	require.Nil(t, segLog.Close())
+
+	ids, err := segmentIDs(config.Dir)
+	require.Nil(t, err)
+	require.Greater(t, len(ids), 1)
+
+	segLog, err = openSegmentLog(config)
+	require.Nil(t, err)
+	defer segLog.Close()
+
+	require.False(t, segLog.Has("00"))
+	for i := 1; i < 20; i++ {
+		require.True(t, segLog.Has(hex.EncodeToString([]byte{byte(i)})))
+	}
+}
+
+func TestSegmentLogRecoversTornWrite(t *testing.T) {
+	config := DiskStoreConfig{Dir: t.TempDir()}
+
+	segLog, err := openSegmentLog(config)
+	require.Nil(t, err)
+	require.Nil(t, segLog.Put("a", []byte("first")))
+	require.Nil(t, segLog.Put("b", []byte("second")))
+	require.Nil(t, segLog.Close())
+
+	path := filepath.Join(config.Dir, "000001.seg")
+	info, err := os.Stat(path)
+	require.Nil(t, err)
+
+	// simulate a crash in the middle of appending the second record
+	require.Nil(t, os.Truncate(path, info.Size()-3))
+
+	segLog, err = openSegmentLog(config)
+	require.Nil(t, err)
+
+	value, err := segLog.Get("a")
+	require.Nil(t, err)
+	require.Equal(t, []byte("first"), value)
+	require.False(t, segLog.Has("b"))
+
+	require.Nil(t, segLog.Put("c", []byte("third")))
+	require.Nil(t, segLog.Close())
+
+	segLog, err = openSegmentLog(config)
+	require.Nil(t, err)
+	defer segLog.Close()
+
+	value, err = segLog.Get("c")
+	require.Nil(t, err)
+	require.Equal(t, []byte("third"), value)
+}
+
+func TestSegmentLogRecoversOversizedLength(t *testing.T) {
+	config := DiskStoreConfig{Dir: t.TempDir()}
+
+	segLog, err := openSegmentLog(config)
+	require.Nil(t, err)
+	require.Nil(t, segLog.Put("a", []byte("first")))
+	require.Nil(t, segLog.Close())
+
+	// a torn header claiming a payload far beyond the end of the segment
+	header := make([]byte, recordHeaderLen)
+	binary.LittleEndian.PutUint32(header[4:8], math.MaxUint32)
+
+	path := filepath.Join(config.Dir, "000001.seg")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.Nil(t, err)
+	_, err = file.Write(header)
+	require.Nil(t, err)
+	require.Nil(t, file.Close())
+
+	segLog, err = openSegmentLog(config)
+	require.Nil(t, err)
+	defer segLog.Close()
+
+	value, err := segLog.Get("a")
+	require.Nil(t, err)
+	require.Equal(t, []byte("first"), value)
+
+	info, err := os.Stat(path)
+	require.Nil(t, err)
+	require.Equal(t, int64(len(encodeRecord(opPut, "a", []byte("first")))), info.Size())
+}
@@ -0,0 +1,355 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+)
+
+const (
+	// roundTimeout is how long a round may last before the validators move
+	// on to the next one.
+	roundTimeout = 2 * blockTime
+	// maxFutureVotes bounds the votes kept for heights above the one being
+	// finalized.
+	maxFutureVotes = 1024
+)
+
+// roundVotes holds the votes of a round by the public key of the validator,
+// the first vote of a validator counts.
+type roundVotes struct {
+	prevotes   map[string]*blockchain.Vote
+	precommits map[string]*blockchain.Vote
+}
+
+func (votes *roundVotes) of(voteType blockchain.Vote_Type) map[string]*blockchain.Vote {
+	if voteType == blockchain.Vote_PRECOMMIT {
+		return votes.precommits
+	}
+	return votes.prevotes
+}
+
+// quorum returns the block hash more than two thirds of the validators voted
+// for, nil when there is none.
+func quorum(votes map[string]*blockchain.Vote, validators int) []byte {
+	counts := make(map[string]int)
+	for _, vote := range votes {
+		counts[string(vote.BlockHash)]++
+		if hasQuorum(counts[string(vote.BlockHash)], validators) {
+			return vote.BlockHash
+		}
+	}
+
+	return nil
+}
+
+// finality finalizes the main chain one height at a time. In each round the
+// validators prevote the block at the height, precommit it once more than two
+// thirds prevoted it and finalize it once more than two thirds precommitted
+// it. A validator that precommitted a block stays locked on it until more than
+// two thirds prevote another block in a later round. Nodes outside the
+// validator set only tally the votes.
+type finality struct {
+	lock  sync.Mutex
+	chain *Chain
+	key   *crypto.PrivateKey
+
+	height      int
+	round       int32
+	started     time.Time
+	lockedHash  []byte
+	lockedRound int32
+	rounds      map[int32]*roundVotes
+	future      []*blockchain.Vote
+
+	// outbox holds the votes cast by this validator until they are
+	// broadcast.
+	outbox []*blockchain.Vote
+	wake   chan struct{}
+}
+
+func newFinality(chain *Chain, key *crypto.PrivateKey) *finality {
+	engine := &finality{
+		chain: chain,
+		wake:  make(chan struct{}, 1),
+	}
+	if key != nil && chain.genesis.IsValidator(key.Public().Bytes()) {
+		engine.key = key
+	}
+	engine.reset(chain.Finalized().Height + 1)
+
+	return engine
+}
+
+// Height returns the height being finalized.
+func (engine *finality) Height() int {
+	engine.lock.Lock()
+	defer engine.lock.Unlock()
+
+	return engine.height
+}
+
+// AddVote checks and records a vote of a validator. Votes for heights above
+// the one being finalized are kept until the engine gets there.
+func (engine *finality) AddVote(vote *blockchain.Vote) error {
+	if !isValidator(engine.chain.validators(int(vote.Height)), vote.PublicKey) {
+		return fmt.Errorf("vote from unknown or slashed validator %x", vote.PublicKey)
+	}
+	if !types.VerifyVote(vote) {
+		return fmt.Errorf("invalid vote signature")
+	}
+
+	engine.lock.Lock()
+	defer engine.lock.Unlock()
+
+	switch {
+	case int(vote.Height) < engine.height:
+		return nil
+	case int(vote.Height) > engine.height:
+		if len(engine.future) < maxFutureVotes {
+			engine.future = append(engine.future, vote)
+		}
+		return nil
+	}
+
+	engine.record(vote)
+	engine.step()
+
+	return nil
+}
+
+// Votes returns the votes cast since the last call.
+func (engine *finality) Votes() []*blockchain.Vote {
+	engine.lock.Lock()
+	defer engine.lock.Unlock()
+
+	votes := engine.outbox
+	engine.outbox = nil
+
+	return votes
+}
+
+// Wake lets the engine vote on the blocks connected since the last call.
+func (engine *finality) Wake() {
+	engine.lock.Lock()
+	defer engine.lock.Unlock()
+
+	engine.step()
+}
+
+// expire moves to the next round when the current one lasted longer than
+// roundTimeout.
+func (engine *finality) expire(now time.Time) {
+	engine.lock.Lock()
+	defer engine.lock.Unlock()
+
+	if now.Sub(engine.started) < roundTimeout {
+		return
+	}
+
+	engine.enterRound(engine.round + 1)
+	engine.step()
+}
+
+// BlockConnected is called while the chain is locked, so it only wakes the
+// run loop.
+func (engine *finality) BlockConnected(block *blockchain.Block) {
+	select {
+	case engine.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (engine *finality) BlockDisconnected(block *blockchain.Block) {
+	engine.BlockConnected(block)
+}
+
+func (engine *finality) run(quit <-chan struct{}, broadcast func([]*blockchain.Vote)) {
+	ticker := time.NewTicker(roundTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-engine.wake:
+			engine.Wake()
+		case now := <-ticker.C:
+			engine.expire(now)
+		case <-quit:
+			return
+		}
+
+		broadcast(engine.Votes())
+	}
+}
+
+func (engine *finality) reset(height int) {
+	engine.height = height
+	engine.lockedHash = nil
+	engine.lockedRound = -1
+	engine.rounds = make(map[int32]*roundVotes)
+	engine.enterRound(0)
+
+	future := engine.future
+	engine.future = nil
+	for _, vote := range future {
+		switch {
+		case int(vote.Height) == height:
+			engine.record(vote)
+		case int(vote.Height) > height:
+			engine.future = append(engine.future, vote)
+		}
+	}
+}
+
+func (engine *finality) enterRound(round int32) {
+	engine.round = round
+	engine.started = time.Now()
+}
+
+func (engine *finality) record(vote *blockchain.Vote) {
+	votes, ok := engine.rounds[vote.Round]
+	if !ok {
+		votes = &roundVotes{
+			prevotes:   make(map[string]*blockchain.Vote),
+			precommits: make(map[string]*blockchain.Vote),
+		}
+		engine.rounds[vote.Round] = votes
+	}
+
+	byValidator := votes.of(vote.Type)
+	if _, ok := byValidator[string(vote.PublicKey)]; !ok {
+		byValidator[string(vote.PublicKey)] = vote
+	}
+}
+
+// step advances the engine as far as the votes and the chain allow.
+func (engine *finality) step() {
+	for engine.catchUp() || engine.tryCommit() || engine.tryPrecommit() || engine.tryPrevote() {
+	}
+}
+
+// catchUp moves past the heights finalized by commits received from peers.
+func (engine *finality) catchUp() bool {
+	finalized := engine.chain.Finalized()
+	if finalized.Height < engine.height {
+		return false
+	}
+
+	engine.reset(finalized.Height + 1)
+	return true
+}
+
+func (engine *finality) tryCommit() bool {
+	for round, votes := range engine.rounds {
+		hash := quorum(votes.precommits, len(engine.chain.validators(engine.height)))
+		if hash == nil {
+			continue
+		}
+
+		commit := &blockchain.Commit{
+			Height:    int32(engine.height),
+			Round:     round,
+			BlockHash: hash,
+		}
+		for _, vote := range votes.precommits {
+			if bytes.Equal(vote.BlockHash, hash) {
+				commit.Precommits = append(commit.Precommits, vote)
+			}
+		}
+		sort.Slice(commit.Precommits, func(i, j int) bool {
+			return bytes.Compare(commit.Precommits[i].PublicKey, commit.Precommits[j].PublicKey) < 0
+		})
+
+		// the block may not have reached us yet
+		if err := engine.chain.Finalize(commit); err != nil {
+			continue
+		}
+
+		engine.reset(engine.height + 1)
+		return true
+	}
+
+	return false
+}
+
+func (engine *finality) tryPrecommit() bool {
+	if !engine.voting() {
+		return false
+	}
+
+	for round, votes := range engine.rounds {
+		if round < engine.round {
+			continue
+		}
+		if _, ok := votes.precommits[string(engine.key.Public().Bytes())]; ok {
+			continue
+		}
+
+		hash := quorum(votes.prevotes, len(engine.chain.validators(engine.height)))
+		if hash == nil {
+			continue
+		}
+		if _, err := engine.chain.GetBlockByHash(hash); err != nil {
+			continue
+		}
+
+		engine.lockedHash = hash
+		engine.lockedRound = round
+		if round > engine.round {
+			engine.enterRound(round)
+		}
+		engine.vote(blockchain.Vote_PRECOMMIT, round, hash)
+		return true
+	}
+
+	return false
+}
+
+func (engine *finality) tryPrevote() bool {
+	if !engine.voting() {
+		return false
+	}
+
+	if votes, ok := engine.rounds[engine.round]; ok {
+		if _, ok := votes.prevotes[string(engine.key.Public().Bytes())]; ok {
+			return false
+		}
+	}
+
+	hash := engine.lockedHash
+	if hash == nil {
+		header, err := engine.chain.GetHeaderByHeight(engine.height)
+		if err != nil {
+			return false
+		}
+		hash = types.HashHeader(header)
+	}
+
+	engine.vote(blockchain.Vote_PREVOTE, engine.round, hash)
+	return true
+}
+
+// voting reports whether this node is a validator at the height being
+// finalized, a slashed validator stops voting.
+func (engine *finality) voting() bool {
+	return engine.key != nil && isValidator(engine.chain.validators(engine.height), engine.key.Public().Bytes())
+}
+
+func (engine *finality) vote(voteType blockchain.Vote_Type, round int32, hash []byte) {
+	vote := &blockchain.Vote{
+		Type:      voteType,
+		Height:    int32(engine.height),
+		Round:     round,
+		BlockHash: hash,
+	}
+	types.SignVote(engine.key, vote)
+
+	engine.record(vote)
+	engine.outbox = append(engine.outbox, vote)
+}
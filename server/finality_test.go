@@ -0,0 +1,262 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newValidatorSet(n int) ([]*crypto.PrivateKey, Genesis) {
+	keys := []*crypto.PrivateKey{}
+	genesis := Genesis{}
+	for i := 0; i < n; i++ {
+		keys = append(keys, crypto.GeneratePrivateKey())
+		genesis.Validators = append(genesis.Validators, keys[i].Public())
+	}
+
+	return keys, genesis
+}
+
+// proposeBlock lets the scheduled validator propose a block on the first chain
+// and adds it to every chain.
+func proposeBlock(t *testing.T, keys []*crypto.PrivateKey, chains ...*Chain) *blockchain.Block {
+	height := chains[0].Height() + 1
+	block, err := chains[0].NewBlock(nil, nil)
+	require.Nil(t, err)
+	types.SignBlock(keys[height%len(keys)], block)
+
+	for _, chain := range chains {
+		require.Nil(t, chain.AddBlock(block))
+	}
+
+	return block
+}
+
+func signedVote(key *crypto.PrivateKey, voteType blockchain.Vote_Type, height int, hash []byte) *blockchain.Vote {
+	vote := &blockchain.Vote{
+		Type:      voteType,
+		Height:    int32(height),
+		BlockHash: hash,
+	}
+	types.SignVote(key, vote)
+
+	return vote
+}
+
+// deliver hands every vote cast by an engine to all the engines until none of
+// them has anything left to say.
+func deliver(t *testing.T, engines ...*finality) {
+	for {
+		votes := []*blockchain.Vote{}
+		for _, engine := range engines {
+			votes = append(votes, engine.Votes()...)
+		}
+		if len(votes) == 0 {
+			return
+		}
+
+		for _, vote := range votes {
+			for _, engine := range engines {
+				require.Nil(t, engine.AddVote(vote))
+			}
+		}
+	}
+}
+
+func TestFinality(t *testing.T) {
+	keys, genesis := newValidatorSet(4)
+
+	chains := []*Chain{}
+	engines := []*finality{}
+	for _, key := range keys {
+		chain := NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithGenesis(genesis))
+		chains = append(chains, chain)
+		engines = append(engines, newFinality(chain, key))
+	}
+
+	blocks := []*blockchain.Block{}
+	for i := 0; i < 3; i++ {
+		blocks = append(blocks, proposeBlock(t, keys, chains...))
+	}
+
+	// two of the four validators are not enough
+	for _, engine := range engines[:2] {
+		engine.Wake()
+	}
+	deliver(t, engines[:2]...)
+	require.Equal(t, 0, chains[0].Finalized().Height)
+
+	// once the round times out the other two join in
+	for _, engine := range engines {
+		engine.expire(time.Now().Add(roundTimeout))
+	}
+	deliver(t, engines...)
+
+	for _, chain := range chains {
+		require.Equal(t, 3, chain.Finalized().Height)
+
+		for i, block := range blocks {
+			commit, err := chain.GetCommit(types.HashBlock(block))
+			require.Nil(t, err)
+			require.NotNil(t, commit)
+			require.Equal(t, int32(i+1), commit.Height)
+			require.GreaterOrEqual(t, len(commit.Precommits), 3)
+			require.Nil(t, verifyCommit(commit, genesis.Validators))
+		}
+	}
+
+	proposeBlock(t, keys, chains...)
+	for _, engine := range engines {
+		engine.Wake()
+	}
+	deliver(t, engines...)
+	require.Equal(t, 4, chains[3].Finalized().Height)
+}
+
+func TestFinalityLocksOnPrecommittedBlock(t *testing.T) {
+	keys, genesis := newValidatorSet(4)
+	chain := NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithGenesis(genesis))
+	engine := newFinality(chain, keys[0])
+
+	block := proposeBlock(t, keys, chain)
+	engine.Wake()
+	require.Len(t, engine.Votes(), 1)
+
+	for _, key := range keys[1:3] {
+		require.Nil(t, engine.AddVote(signedVote(key, blockchain.Vote_PREVOTE, 1, types.HashBlock(block))))
+	}
+	votes := engine.Votes()
+	require.Len(t, votes, 1)
+	require.Equal(t, blockchain.Vote_PRECOMMIT, votes[0].Type)
+
+	// the next round prevotes the locked block again
+	engine.lock.Lock()
+	engine.enterRound(1)
+	engine.step()
+	engine.lock.Unlock()
+
+	votes = engine.Votes()
+	require.Len(t, votes, 1)
+	require.Equal(t, blockchain.Vote_PREVOTE, votes[0].Type)
+	require.Equal(t, int32(1), votes[0].Round)
+	require.Equal(t, types.HashBlock(block), votes[0].BlockHash)
+}
+
+func TestFinalizeChecksCommit(t *testing.T) {
+	var (
+		keys, genesis = newValidatorSet(4)
+		chain         = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithGenesis(genesis))
+		block         = proposeBlock(t, keys, chain)
+		hash          = types.HashBlock(block)
+	)
+
+	commit := func(votes ...*blockchain.Vote) *blockchain.Commit {
+		return &blockchain.Commit{Height: 1, BlockHash: hash, Precommits: votes}
+	}
+	tampered := signedVote(keys[2], blockchain.Vote_PRECOMMIT, 1, hash)
+	tampered.Signature[0] ^= 1
+
+	cases := map[string]*blockchain.Commit{
+		"too few signatures": commit(
+			signedVote(keys[0], blockchain.Vote_PRECOMMIT, 1, hash),
+			signedVote(keys[1], blockchain.Vote_PRECOMMIT, 1, hash),
+		),
+		"same validator twice": commit(
+			signedVote(keys[0], blockchain.Vote_PRECOMMIT, 1, hash),
+			signedVote(keys[1], blockchain.Vote_PRECOMMIT, 1, hash),
+			signedVote(keys[1], blockchain.Vote_PRECOMMIT, 1, hash),
+		),
+		"prevotes": commit(
+			signedVote(keys[0], blockchain.Vote_PREVOTE, 1, hash),
+			signedVote(keys[1], blockchain.Vote_PREVOTE, 1, hash),
+			signedVote(keys[2], blockchain.Vote_PREVOTE, 1, hash),
+		),
+		"unknown validator": commit(
+			signedVote(keys[0], blockchain.Vote_PRECOMMIT, 1, hash),
+			signedVote(keys[1], blockchain.Vote_PRECOMMIT, 1, hash),
+			signedVote(crypto.GeneratePrivateKey(), blockchain.Vote_PRECOMMIT, 1, hash),
+		),
+		"invalid signature": commit(
+			signedVote(keys[0], blockchain.Vote_PRECOMMIT, 1, hash),
+			signedVote(keys[1], blockchain.Vote_PRECOMMIT, 1, hash),
+			tampered,
+		),
+	}
+
+	for name, commit := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.NotNil(t, chain.Finalize(commit))
+			require.Equal(t, 0, chain.Finalized().Height)
+		})
+	}
+
+	require.Nil(t, chain.Finalize(commit(
+		signedVote(keys[0], blockchain.Vote_PRECOMMIT, 1, hash),
+		signedVote(keys[1], blockchain.Vote_PRECOMMIT, 1, hash),
+		signedVote(keys[3], blockchain.Vote_PRECOMMIT, 1, hash),
+	)))
+	require.Equal(t, 1, chain.Finalized().Height)
+}
+
+func TestFinalizedBlocksAreNeverReverted(t *testing.T) {
+	var (
+		keys, genesis = newValidatorSet(1)
+		config        = DiskStoreConfig{Dir: t.TempDir()}
+	)
+
+	store, err := OpenDiskStore(config)
+	require.Nil(t, err)
+
+	chain, err := OpenChain(store.Blocks, store.Transactions, store.UTXOs, WithGenesis(genesis))
+	require.Nil(t, err)
+
+	genesisBlock, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	// a side branch with a coinbase at height 1
+	side, err := chain.NewBlock(keys[0].Public().Address().Bytes(), nil)
+	require.Nil(t, err)
+	types.SignBlock(keys[0], side)
+
+	main := proposeBlock(t, keys, chain)
+	require.Nil(t, chain.AddBlock(side))
+	require.Equal(t, types.HashBlock(main), types.HashHeader(chain.tip.Header))
+
+	// finalizing the side branch moves the main chain onto it
+	require.Nil(t, chain.Finalize(&blockchain.Commit{
+		Height:     1,
+		BlockHash:  types.HashBlock(side),
+		Precommits: []*blockchain.Vote{signedVote(keys[0], blockchain.Vote_PRECOMMIT, 1, types.HashBlock(side))},
+	}))
+	require.Equal(t, 1, chain.Finalized().Height)
+	require.Equal(t, types.HashBlock(side), types.HashHeader(chain.tip.Header))
+
+	require.NotNil(t, chain.DisconnectTip())
+	for _, parent := range []*blockchain.Block{main, genesisBlock} {
+		block := childBlock(parent)
+		types.SignBlock(keys[0], block)
+		require.NotNil(t, chain.AddBlock(block))
+	}
+	require.NotNil(t, chain.Finalize(&blockchain.Commit{
+		Height:     1,
+		BlockHash:  types.HashBlock(main),
+		Precommits: []*blockchain.Vote{signedVote(keys[0], blockchain.Vote_PRECOMMIT, 1, types.HashBlock(main))},
+	}))
+
+	proposeBlock(t, keys, chain)
+	require.Nil(t, chain.DisconnectTip())
+	require.NotNil(t, chain.DisconnectTip())
+	require.Nil(t, store.Close())
+
+	store, err = OpenDiskStore(config)
+	require.Nil(t, err)
+	defer store.Close()
+
+	chain, err = OpenChain(store.Blocks, store.Transactions, store.UTXOs, WithGenesis(genesis))
+	require.Nil(t, err)
+	require.Equal(t, types.HashBlock(side), types.HashHeader(chain.Finalized().Header))
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+)
+
+const (
+	// locatorDenseHashes is how many of the most recent blocks a locator
+	// lists one by one before the gaps start doubling.
+	locatorDenseHashes = 10
+	// maxInvalidBlocks bounds the invalid blocks remembered.
+	maxInvalidBlocks = 1024
+)
+
+// BlockNode is an entry of the block tree, the main chain and every known side
+// branch share their common ancestors.
+type BlockNode struct {
+	Hash   string
+	Header *blockchain.Header
+	Height int
+	Parent *BlockNode
+	// Work is the sum of the difficulties of the block and its ancestors.
+	Work *big.Int
+}
+
+// ForkChoice decides which branch of the block tree is the main chain.
+type ForkChoice interface {
+	// Better reports whether the branch ending at candidate should replace
+	// the branch ending at current.
+	Better(candidate, current *BlockNode) bool
+}
+
+// LongestChain prefers the highest branch, ties are won by the branch that
+// was seen first.
+type LongestChain struct{}
+
+func (LongestChain) Better(candidate, current *BlockNode) bool {
+	return candidate.Height > current.Height
+}
+
+// MostWork prefers the branch with the most accumulated proof of work, ties
+// are won by the branch that was seen first.
+type MostWork struct{}
+
+func (MostWork) Better(candidate, current *BlockNode) bool {
+	return candidate.Work.Cmp(current.Work) > 0
+}
+
+type ChainListener interface {
+	BlockConnected(block *blockchain.Block)
+	BlockDisconnected(block *blockchain.Block)
+}
+
+// reorganize disconnects the main chain down to the fork point with the branch
+// ending at node and connects that branch instead. If a block of the new branch
+// turns out to be invalid, the branch is dropped and the old main chain is
+// restored.
+func (chain *Chain) reorganize(node *BlockNode) error {
+	fork := findFork(chain.tip, node)
+	if fork.Height < chain.finalized.Height {
+		return fmt.Errorf("can not reorganize below the finalized height %d", chain.finalized.Height)
+	}
+
+	branch := []*BlockNode{}
+	for n := node; n != fork; n = n.Parent {
+		branch = append([]*BlockNode{n}, branch...)
+	}
+
+	disconnected := []*BlockNode{}
+	for chain.tip != fork {
+		disconnected = append(disconnected, chain.tip)
+		if err := chain.disconnectTip(); err != nil {
+			return err
+		}
+	}
+
+	for _, n := range branch {
+		err := chain.connectNode(n)
+		if err == nil {
+			continue
+		}
+
+		chain.dropInvalid(n)
+
+		for chain.tip != fork {
+			if err := chain.disconnectTip(); err != nil {
+				return err
+			}
+		}
+		for j := len(disconnected) - 1; j >= 0; j-- {
+			if err := chain.connectNode(disconnected[j]); err != nil {
+				return err
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// dropInvalid removes the invalid node and every known block descending from
+// it from the index, and remembers them as invalid.
+func (chain *Chain) dropInvalid(invalid *BlockNode) {
+	for hash, node := range chain.index {
+		ancestor := node
+		for ancestor != nil && ancestor.Height > invalid.Height {
+			ancestor = ancestor.Parent
+		}
+		if ancestor == invalid {
+			delete(chain.index, hash)
+			chain.invalid.Add(hash)
+		}
+	}
+}
+
+func (chain *Chain) connectNode(node *BlockNode) error {
+	block, err := chain.blockStore.Get(node.Hash)
+	if err != nil {
+		return err
+	}
+
+	if err := chain.validateStaker(block, node.Parent); err != nil {
+		return err
+	}
+
+	if err := chain.validateTransactions(block.Transactions, node.Height); err != nil {
+		return err
+	}
+
+	return chain.connectBlock(node, block)
+}
+
+// descendsFromFinalized reports whether the node is the finalized block or one
+// of its descendants.
+func (chain *Chain) descendsFromFinalized(node *BlockNode) bool {
+	for node != nil && node.Height > chain.finalized.Height {
+		node = node.Parent
+	}
+
+	return node == chain.finalized
+}
+
+func findFork(a, b *BlockNode) *BlockNode {
+	for a.Height > b.Height {
+		a = a.Parent
+	}
+	for b.Height > a.Height {
+		b = b.Parent
+	}
+	for a != b {
+		a = a.Parent
+		b = b.Parent
+	}
+
+	return a
+}
+
+// Locator returns the hashes of the tip and of its ancestors on the main chain,
+// one by one for the most recent blocks and then with gaps doubling in size,
+// ending with the genesis block. A peer finds the last block both chains share
+// in it whatever the length of the branch it is on.
+func (chain *Chain) Locator() [][]byte {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	locator := [][]byte{}
+	step := 1
+	for height := chain.tip.Height; height > 0; height -= step {
+		locator = append(locator, types.HashHeader(chain.headers.Get(height)))
+		if len(locator) >= locatorDenseHashes {
+			step *= 2
+		}
+	}
+
+	return append(locator, types.HashHeader(chain.headers.Get(0)))
+}
+
+// LocateFork returns the height of the first block of the locator that is on
+// the main chain, the genesis block when there is none.
+func (chain *Chain) LocateFork(locator [][]byte) int {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	for _, hash := range locator {
+		node, ok := chain.index[hex.EncodeToString(hash)]
+		if !ok || node.Height > chain.tip.Height {
+			continue
+		}
+
+		if bytes.Equal(types.HashHeader(chain.headers.Get(node.Height)), hash) {
+			return node.Height
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingListener struct {
+	connected    []*blockchain.Block
+	disconnected []*blockchain.Block
+}
+
+func (listener *recordingListener) BlockConnected(block *blockchain.Block) {
+	listener.connected = append(listener.connected, block)
+}
+
+func (listener *recordingListener) BlockDisconnected(block *blockchain.Block) {
+	listener.disconnected = append(listener.disconnected, block)
+}
+
+func childBlock(parent *blockchain.Block, transactions ...*blockchain.Transaction) *blockchain.Block {
+	block := &blockchain.Block{
+		Header: &blockchain.Header{
+			Version:      1,
+			Height:       parent.Header.Height + 1,
+			PreviousHash: types.HashBlock(parent),
+			Timestamp:    parent.Header.Timestamp + 1,
+		},
+		Transactions: transactions,
+	}
+	types.SignBlock(crypto.GeneratePrivateKey(), block)
+
+	return block
+}
+
+func TestReorganizeToLongerBranch(t *testing.T) {
+	var (
+		chain    = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		listener = &recordingListener{}
+		tx       = genesisSpend(t, chain, 100)
+		txHash   = hex.EncodeToString(types.HashTransaction(tx))
+	)
+	chain.Subscribe(listener)
+
+	genesis, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+	genesisOutput := types.NewOutPoint(types.HashTransaction(genesis.Transactions[0]), 0)
+
+	a1 := childBlock(genesis, tx)
+	a2 := childBlock(a1)
+	require.Nil(t, chain.AddBlock(a1))
+	require.Nil(t, chain.AddBlock(a2))
+
+	utxo, err := chain.utxoStore.Get(genesisOutput)
+	require.Nil(t, err)
+	require.True(t, utxo.Spent)
+
+	b1 := childBlock(genesis)
+	b2 := childBlock(b1)
+	require.Nil(t, chain.AddBlock(b1))
+	require.Nil(t, chain.AddBlock(b2))
+
+	tip, err := chain.GetBlockByHeight(2)
+	require.Nil(t, err)
+	require.Equal(t, types.HashBlock(a2), types.HashBlock(tip))
+
+	b3 := childBlock(b2)
+	require.Nil(t, chain.AddBlock(b3))
+	require.Equal(t, 3, chain.Height())
+
+	for height, block := range []*blockchain.Block{genesis, b1, b2, b3} {
+		fetched, err := chain.GetBlockByHeight(height)
+		require.Nil(t, err)
+		require.Equal(t, types.HashBlock(block), types.HashBlock(fetched))
+	}
+
+	utxo, err = chain.utxoStore.Get(genesisOutput)
+	require.Nil(t, err)
+	require.False(t, utxo.Spent)
+
+	_, err = chain.utxoStore.Get(types.NewOutPoint(types.HashTransaction(tx), 0))
+	require.NotNil(t, err)
+	_, err = chain.txStore.Get(txHash)
+	require.NotNil(t, err)
+
+	require.Len(t, listener.disconnected, 2)
+	require.Equal(t, types.HashBlock(a2), types.HashBlock(listener.disconnected[0]))
+	require.Equal(t, types.HashBlock(a1), types.HashBlock(listener.disconnected[1]))
+}
+
+func TestReorganizeToInvalidBranch(t *testing.T) {
+	var (
+		chain  = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		spend1 = genesisSpend(t, chain, 100)
+		spend2 = genesisSpend(t, chain, 200)
+	)
+
+	genesis, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	a1 := childBlock(genesis, spend1)
+	require.Nil(t, chain.AddBlock(a1))
+
+	// b2 spends the genesis output a second time within its own branch
+	b1 := childBlock(genesis, spend2)
+	b2 := childBlock(b1, spend1)
+	require.Nil(t, chain.AddBlock(b1))
+	require.NotNil(t, chain.AddBlock(b2))
+
+	require.Equal(t, 1, chain.Height())
+	tip, err := chain.GetBlockByHeight(1)
+	require.Nil(t, err)
+	require.Equal(t, types.HashBlock(a1), types.HashBlock(tip))
+
+	_, err = chain.txStore.Get(hex.EncodeToString(types.HashTransaction(spend1)))
+	require.Nil(t, err)
+	_, err = chain.txStore.Get(hex.EncodeToString(types.HashTransaction(spend2)))
+	require.NotNil(t, err)
+}
+
+func TestReorganizeDropsInvalidSubtree(t *testing.T) {
+	var (
+		chain  = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		spend1 = genesisSpend(t, chain, 100)
+		spend2 = genesisSpend(t, chain, 200)
+	)
+
+	genesis, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	a1 := childBlock(genesis, spend1)
+	a2 := childBlock(a1)
+	a3 := childBlock(a2)
+	for _, block := range []*blockchain.Block{a1, a2, a3} {
+		require.Nil(t, chain.AddBlock(block))
+	}
+
+	// b2 is invalid, which is only found once b4 makes its branch the longest
+	b1 := childBlock(genesis, spend2)
+	b2 := childBlock(b1, spend1)
+	b3 := childBlock(b2)
+	sibling := childBlock(b2)
+	sibling.Header.Timestamp++
+	types.SignBlock(crypto.GeneratePrivateKey(), sibling)
+	for _, block := range []*blockchain.Block{b1, b2, b3, sibling} {
+		require.Nil(t, chain.AddBlock(block))
+	}
+	require.NotNil(t, chain.AddBlock(childBlock(b3)))
+	require.Equal(t, types.HashBlock(a3), types.HashHeader(chain.tip.Header))
+
+	require.True(t, chain.HasBlock(types.HashBlock(b1)))
+	for _, block := range []*blockchain.Block{b2, b3, sibling} {
+		require.False(t, chain.HasBlock(types.HashBlock(block)))
+		require.True(t, chain.IsInvalid(types.HashBlock(block)))
+	}
+
+	// neither the invalid blocks nor their descendants are tried again
+	require.NotNil(t, chain.AddBlock(b2))
+	nephew := childBlock(sibling)
+	require.NotNil(t, chain.AddBlock(nephew))
+	require.True(t, chain.IsInvalid(types.HashBlock(nephew)))
+	require.Equal(t, 3, chain.Height())
+}
+
+func TestUnknownParentIsRejected(t *testing.T) {
+	chain := NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+
+	genesis, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	orphan := childBlock(childBlock(genesis))
+	require.NotNil(t, chain.AddBlock(orphan))
+	require.Equal(t, 0, chain.Height())
+}
+
+func TestLocator(t *testing.T) {
+	var (
+		chain = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		other = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+	)
+
+	block, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+	for i := 0; i < 40; i++ {
+		block = childBlock(block)
+		require.Nil(t, chain.AddBlock(block))
+		if i < 25 {
+			require.Nil(t, other.AddBlock(block))
+		}
+	}
+
+	heights := []int{}
+	for _, hash := range chain.Locator() {
+		block, err := chain.GetBlockByHash(hash)
+		require.Nil(t, err)
+		heights = append(heights, int(block.Header.Height))
+	}
+	require.Equal(t, []int{40, 39, 38, 37, 36, 35, 34, 33, 32, 31, 29, 25, 17, 1, 0}, heights)
+
+	// the other chain shares the first 25 blocks, then goes its own way
+	block, err = other.GetBlockByHeight(25)
+	require.Nil(t, err)
+	fork := childBlock(block)
+	fork.Header.Timestamp++
+	types.SignBlock(crypto.GeneratePrivateKey(), fork)
+	require.Nil(t, other.AddBlock(fork))
+
+	require.Equal(t, 25, other.LocateFork(chain.Locator()))
+	require.Equal(t, 25, chain.LocateFork(other.Locator()))
+	require.Equal(t, 0, chain.LocateFork([][]byte{types.HashBlock(fork)}))
+}
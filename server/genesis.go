@@ -0,0 +1,130 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+)
+
+const (
+	defaultInitialDifficulty = 1 << 16
+	defaultRetargetInterval  = 20
+)
+
+// ConsensusMode selects how the blocks of a network are produced.
+type ConsensusMode int
+
+const (
+	// ConsensusAuthority requires blocks to be signed, by the scheduled
+	// validator when the genesis has a validator set.
+	ConsensusAuthority ConsensusMode = iota
+	// ConsensusWork requires blocks to carry a proof of work, their signature
+	// is not checked and the branch with the most work is the main chain.
+	ConsensusWork
+	// ConsensusStake requires blocks to be signed by the staker picked in
+	// proportion to the bonded stakes, any key may sign while nothing is
+	// staked.
+	ConsensusStake
+)
+
+// WorkParams are the proof-of-work parameters of a network, zero values are
+// replaced by defaults.
+type WorkParams struct {
+	// InitialDifficulty is the difficulty of the block at height 1.
+	InitialDifficulty uint64
+	// RetargetInterval is how many blocks are produced between difficulty
+	// adjustments.
+	RetargetInterval int
+	// TargetSpacing is the time the difficulty aims to keep between blocks.
+	TargetSpacing time.Duration
+}
+
+// Genesis holds the parameters every node of a network has to agree on from
+// the genesis block on.
+type Genesis struct {
+	Consensus ConsensusMode
+	// Validators is the proof-of-authority validator set, they propose blocks
+	// in turn. An empty set lets any key propose blocks.
+	Validators []*crypto.PublicKey
+	Work       WorkParams
+}
+
+func WithGenesis(genesis Genesis) ChainOption {
+	if genesis.Work.InitialDifficulty == 0 {
+		genesis.Work.InitialDifficulty = defaultInitialDifficulty
+	}
+	if genesis.Work.RetargetInterval <= 0 {
+		genesis.Work.RetargetInterval = defaultRetargetInterval
+	}
+	if genesis.Work.TargetSpacing <= 0 {
+		genesis.Work.TargetSpacing = blockTime
+	}
+
+	return func(chain *Chain) {
+		chain.genesis = genesis
+	}
+}
+
+// commitment hashes the parameters that apply to the consensus mode along
+// with the monetary policy, it is committed to in the genesis block so that
+// networks set up differently do not share a genesis hash.
+func (genesis Genesis) commitment(policy MonetaryPolicy) []byte {
+	hash := sha256.New()
+	binary.Write(hash, binary.BigEndian, policy.InitialSubsidy)
+	binary.Write(hash, binary.BigEndian, int64(policy.HalvingInterval))
+	binary.Write(hash, binary.BigEndian, policy.TailEmission)
+	binary.Write(hash, binary.BigEndian, policy.MaxSupply)
+	binary.Write(hash, binary.BigEndian, int64(genesis.Consensus))
+
+	switch genesis.Consensus {
+	case ConsensusAuthority:
+		for _, validator := range genesis.Validators {
+			hash.Write(validator.Bytes())
+		}
+	case ConsensusWork:
+		binary.Write(hash, binary.BigEndian, genesis.Work.InitialDifficulty)
+		binary.Write(hash, binary.BigEndian, int64(genesis.Work.RetargetInterval))
+		binary.Write(hash, binary.BigEndian, int64(genesis.Work.TargetSpacing))
+	}
+
+	return hash.Sum(nil)
+}
+
+// IsValidator reports whether the public key belongs to the validator set.
+func (genesis Genesis) IsValidator(publicKey []byte) bool {
+	return isValidator(genesis.Validators, publicKey)
+}
+
+func isValidator(validators []*crypto.PublicKey, publicKey []byte) bool {
+	for _, validator := range validators {
+		if bytes.Equal(validator.Bytes(), publicKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyBlock checks the block on its own: its signature on authority
+// networks, only its root hash on proof-of-work networks where the work is
+// checked against the block tree.
+func (genesis Genesis) verifyBlock(block *blockchain.Block) error {
+	if genesis.Consensus == ConsensusWork {
+		if !types.VerifyRootHash(block) {
+			return fmt.Errorf("invalid block root hash")
+		}
+		return nil
+	}
+
+	if !types.VerifyBlock(block) {
+		return fmt.Errorf("invalid block signature")
+	}
+
+	return nil
+}
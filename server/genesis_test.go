@@ -0,0 +1,91 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/blockchain/crypto"
+	"github.com/blockchain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainEnforcesProposerSchedule(t *testing.T) {
+	var (
+		validators = []*crypto.PrivateKey{crypto.GeneratePrivateKey(), crypto.GeneratePrivateKey()}
+		genesis    = Genesis{Validators: []*crypto.PublicKey{validators[0].Public(), validators[1].Public()}}
+		config     = DiskStoreConfig{Dir: t.TempDir()}
+	)
+
+	store, err := OpenDiskStore(config)
+	require.Nil(t, err)
+
+	chain, err := OpenChain(store.Blocks, store.Transactions, store.UTXOs, WithGenesis(genesis))
+	require.Nil(t, err)
+
+	for height := 1; height <= 4; height++ {
+		scheduled := validators[height%2]
+		other := validators[(height+1)%2]
+
+		block, err := chain.NewBlock(nil, nil)
+		require.Nil(t, err)
+
+		types.SignBlock(other, block)
+		require.NotNil(t, chain.AddBlock(block))
+		types.SignBlock(crypto.GeneratePrivateKey(), block)
+		require.NotNil(t, chain.AddBlock(block))
+
+		types.SignBlock(scheduled, block)
+		require.Nil(t, chain.AddBlock(block))
+	}
+	require.Nil(t, store.Close())
+
+	store, err = OpenDiskStore(config)
+	require.Nil(t, err)
+	defer store.Close()
+
+	chain, err = OpenChain(store.Blocks, store.Transactions, store.UTXOs, WithGenesis(genesis))
+	require.Nil(t, err)
+	require.Equal(t, 4, chain.Height())
+
+	// the same blocks do not follow a schedule with the validators swapped
+	swapped := Genesis{Validators: []*crypto.PublicKey{validators[1].Public(), validators[0].Public()}}
+	_, err = OpenChain(store.Blocks, store.Transactions, store.UTXOs, WithGenesis(swapped))
+	var corruption *ChainCorruptionError
+	require.ErrorAs(t, err, &corruption)
+}
+
+func TestGenesisCommitsToConsensus(t *testing.T) {
+	var (
+		key      = crypto.GeneratePrivateKey()
+		networks = []Genesis{
+			{},
+			{Validators: []*crypto.PublicKey{key.Public()}},
+			{Consensus: ConsensusStake},
+			{Consensus: ConsensusWork, Work: WorkParams{InitialDifficulty: 1 << 8}},
+			{Consensus: ConsensusWork, Work: WorkParams{InitialDifficulty: 1 << 10}},
+		}
+		hashes = make(map[string]bool)
+	)
+
+	for _, genesis := range networks {
+		hashes[string(types.HashBlock(createGenesisBlock(genesis, DefaultMonetaryPolicy)))] = true
+	}
+	require.Len(t, hashes, len(networks))
+
+	// the parameters of other modes do not matter
+	require.Equal(t,
+		types.HashBlock(createGenesisBlock(Genesis{}, DefaultMonetaryPolicy)),
+		types.HashBlock(createGenesisBlock(Genesis{Work: WorkParams{InitialDifficulty: 1 << 8}}, DefaultMonetaryPolicy)),
+	)
+
+	config := DiskStoreConfig{Dir: t.TempDir()}
+	store, err := OpenDiskStore(config)
+	require.Nil(t, err)
+	defer store.Close()
+
+	_, err = OpenChain(store.Blocks, store.Transactions, store.UTXOs, WithGenesis(networks[3]))
+	require.Nil(t, err)
+
+	_, err = OpenChain(store.Blocks, store.Transactions, store.UTXOs, WithGenesis(networks[4]))
+	var corruption *ChainCorruptionError
+	require.ErrorAs(t, err, &corruption)
+}
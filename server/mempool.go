@@ -0,0 +1,889 @@
+package server
+
+import (
+	"container/heap"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultMempoolMaxBytes = 32 << 20
+	defaultMempoolTTL      = 24 * time.Hour
+	// maxAncestors bounds the chain of pending transactions a transaction may
+	// depend on, which also bounds the size of a package.
+	maxAncestors = 25
+	// blockTransactionsField is the field number of the transactions of a
+	// block.
+	blockTransactionsField = 2
+)
+
+var (
+	ErrTxAlreadyKnown = errors.New("transaction already in the mempool")
+	ErrMempoolFull    = errors.New("mempool is full")
+)
+
+type MempoolConfig struct {
+	// MaxBytes caps the total size of the pending transactions, 0 uses the
+	// default of 32MiB.
+	MaxBytes int
+	// TTL is how long a transaction may stay pending before it expires, 0
+	// uses the default of 24 hours.
+	TTL time.Duration
+	// ReplaceByFee lets a transaction replace the pending transactions it
+	// conflicts with when it pays both a higher fee and a higher fee rate.
+	ReplaceByFee bool
+}
+
+type RemovalReason int
+
+const (
+	// RemovedIncluded is used for transactions confirmed by a block.
+	RemovedIncluded RemovalReason = iota
+	// RemovedConflict is used for transactions spending an output spent by a
+	// block.
+	RemovedConflict
+	// RemovedInvalid is used for transactions no longer valid on top of the
+	// tip for any other reason.
+	RemovedInvalid
+	// RemovedExpired is used for transactions pending for longer than the TTL.
+	RemovedExpired
+	// RemovedEvicted is used for transactions making room for better paying
+	// ones.
+	RemovedEvicted
+	// RemovedReplaced is used for transactions replaced by a conflicting one
+	// paying a higher fee.
+	RemovedReplaced
+	numRemovalReasons
+)
+
+func (reason RemovalReason) String() string {
+	switch reason {
+	case RemovedIncluded:
+		return "included"
+	case RemovedConflict:
+		return "conflict"
+	case RemovedInvalid:
+		return "invalid"
+	case RemovedExpired:
+		return "expired"
+	case RemovedEvicted:
+		return "evicted"
+	case RemovedReplaced:
+		return "replaced"
+	}
+	return fmt.Sprintf("RemovalReason(%d)", int(reason))
+}
+
+type feeRate struct {
+	fee  int64
+	size int
+}
+
+// compare returns -1, 0 or 1 when the fee per byte of rate is lower than,
+// equal to or higher than the one of other.
+func (rate feeRate) compare(other feeRate) int {
+	left, right := rate.fee*int64(other.size), other.fee*int64(rate.size)
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	}
+	return 0
+}
+
+func (rate feeRate) add(entry *mempoolEntry) feeRate {
+	return feeRate{fee: rate.fee + entry.fee, size: rate.size + entry.size}
+}
+
+type mempoolEntry struct {
+	tx    *blockchain.Transaction
+	hash  string
+	fee   int64
+	size  int
+	added time.Time
+
+	// parents and children link the entry to the pending transactions it
+	// spends from and the ones spending from it.
+	parents  map[string]*mempoolEntry
+	children map[string]*mempoolEntry
+
+	// ancestorFee and ancestorSize sum the entry and its pending ancestors,
+	// they rank the entry when a block is filled.
+	ancestorFee  int64
+	ancestorSize int
+}
+
+func (entry *mempoolEntry) rate() feeRate {
+	return feeRate{fee: entry.fee, size: entry.size}
+}
+
+// blockSize is the room the transaction takes in a block, along with the tag
+// and the length it is prefixed with.
+func (entry *mempoolEntry) blockSize() int {
+	return protowire.SizeTag(blockTransactionsField) + protowire.SizeBytes(entry.size)
+}
+
+func (entry *mempoolEntry) ancestorRate() feeRate {
+	return feeRate{fee: entry.ancestorFee, size: entry.ancestorSize}
+}
+
+// evidence reports whether the entry is an evidence transaction. Those pay no
+// fee, yet go first into blocks and are never evicted, as slashing a double
+// signer matters more than any fee.
+func (entry *mempoolEntry) evidence() bool {
+	return entry.tx.Kind == blockchain.Transaction_EVIDENCE
+}
+
+// betterThan orders evidence transactions first and the other entries by fee
+// per byte, the oldest entry first when the fee rates are equal.
+func (entry *mempoolEntry) betterThan(other *mempoolEntry) bool {
+	if entry.evidence() != other.evidence() {
+		return entry.evidence()
+	}
+	if rate := entry.rate().compare(other.rate()); rate != 0 {
+		return rate > 0
+	}
+	if !entry.added.Equal(other.added) {
+		return entry.added.Before(other.added)
+	}
+	return entry.hash < other.hash
+}
+
+// mempoolJournal records the changes made to the pool while a package is
+// admitted, so they can be undone when one of its transactions is rejected.
+type mempoolJournal struct {
+	inserted []*mempoolEntry
+	removed  []*mempoolEntry
+	counters [numRemovalReasons]uint64
+	events   int
+}
+
+type Mempool struct {
+	lock         sync.RWMutex
+	config       MempoolConfig
+	chain        *Chain
+	transactions map[string]*mempoolEntry
+	// byFeeRate holds the entries ordered from the best to the worst fee rate.
+	byFeeRate []*mempoolEntry
+	size      int
+	// spends maps every output spent by a pending transaction to the hash of
+	// that transaction.
+	spends map[types.OutPoint]string
+	// convicted maps every validator convicted by a pending evidence
+	// transaction to the hash of that transaction.
+	convicted map[string]string
+	removed   [numRemovalReasons]uint64
+	journal   *mempoolJournal
+
+	// events holds the changes not yet handed to the subscribers.
+	events      []MempoolEvent
+	subscribers map[*mempoolSubscriber]struct{}
+}
+
+func NewMempool(chain *Chain, config MempoolConfig) *Mempool {
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = defaultMempoolMaxBytes
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaultMempoolTTL
+	}
+
+	return &Mempool{
+		config:       config,
+		chain:        chain,
+		transactions: make(map[string]*mempoolEntry),
+		spends:       make(map[types.OutPoint]string),
+		convicted:    make(map[string]string),
+		subscribers:  make(map[*mempoolSubscriber]struct{}),
+	}
+}
+
+func (pool *Mempool) Len() int {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	return len(pool.transactions)
+}
+
+// Size returns the total size in bytes of the pending transactions.
+func (pool *Mempool) Size() int {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	return pool.size
+}
+
+func (pool *Mempool) Has(transaction *blockchain.Transaction) bool {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	hash := hex.EncodeToString(types.HashTransaction(transaction))
+	_, ok := pool.transactions[hash]
+	return ok
+}
+
+// Remove drops the transaction and, unless it was included in a block, the
+// pending transactions spending from it.
+func (pool *Mempool) Remove(transaction *blockchain.Transaction, reason RemovalReason) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	defer pool.publish()
+
+	if entry, ok := pool.transactions[hex.EncodeToString(types.HashTransaction(transaction))]; ok {
+		pool.remove(entry, reason)
+	}
+}
+
+// Removed returns how many transactions left the pool for the given reason.
+func (pool *Mempool) Removed(reason RemovalReason) uint64 {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	return pool.removed[reason]
+}
+
+// Select returns the pending transactions to include in a block, taking at
+// most maxBytes of it, parents before their children. Transactions are picked by the fee
+// rate of the package formed with their pending ancestors, so a child paying a
+// high fee pulls its parents in. The transactions stay in the pool.
+func (pool *Mempool) Select(maxBytes int) []*blockchain.Transaction {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	return pool.selectPackages(maxBytes)
+}
+
+// packageCandidate is an entry ranked by the fee rate of its package, the
+// entry along with its ancestors that are not selected yet.
+type packageCandidate struct {
+	entry *mempoolEntry
+	rate  feeRate
+}
+
+type packageHeap []packageCandidate
+
+func (candidates packageHeap) Len() int { return len(candidates) }
+
+func (candidates packageHeap) Less(i, j int) bool {
+	if candidates[i].entry.evidence() != candidates[j].entry.evidence() {
+		return candidates[i].entry.evidence()
+	}
+	if rate := candidates[i].rate.compare(candidates[j].rate); rate != 0 {
+		return rate > 0
+	}
+	return candidates[i].entry.betterThan(candidates[j].entry)
+}
+
+func (candidates packageHeap) Swap(i, j int) {
+	candidates[i], candidates[j] = candidates[j], candidates[i]
+}
+
+func (candidates *packageHeap) Push(candidate any) {
+	*candidates = append(*candidates, candidate.(packageCandidate))
+}
+
+func (candidates *packageHeap) Pop() any {
+	old := *candidates
+	candidate := old[len(old)-1]
+	*candidates = old[:len(old)-1]
+	return candidate
+}
+
+// selectPackages picks the package with the highest fee rate that fits in
+// maxBytes until none does. The entries start ranked by their cached ancestor
+// totals. Selecting a package lowers the package rates of the descendants of
+// its transactions, which are kept in modified and ranked again, so a pick
+// costs the size of the package and of its descendants instead of a scan of
+// the pool.
+func (pool *Mempool) selectPackages(maxBytes int) []*blockchain.Transaction {
+	transactions := []*blockchain.Transaction{}
+	selected := make(map[string]bool)
+	modified := make(map[string]feeRate)
+
+	candidates := make(packageHeap, 0, len(pool.byFeeRate))
+	for _, entry := range pool.byFeeRate {
+		candidates = append(candidates, packageCandidate{entry: entry, rate: entry.ancestorRate()})
+	}
+	heap.Init(&candidates)
+
+	for candidates.Len() > 0 {
+		candidate := heap.Pop(&candidates).(packageCandidate)
+		entry := candidate.entry
+		if selected[entry.hash] {
+			continue
+		}
+		// a selected ancestor ranked the entry again with a smaller package
+		if rate, ok := modified[entry.hash]; ok && rate != candidate.rate {
+			continue
+		}
+		// the entry comes back if one of its ancestors gets selected
+		if candidate.rate.size > maxBytes {
+			continue
+		}
+
+		pkg := pool.ancestors(entry, selected)
+		pkg[entry.hash] = entry
+		size := 0
+		for _, member := range pkg {
+			size += member.blockSize()
+		}
+		if size > maxBytes {
+			continue
+		}
+		transactions = appendPackage(transactions, entry, pkg, selected)
+		maxBytes -= size
+
+		for _, member := range pkg {
+			for _, descendant := range descendants([]*mempoolEntry{member}) {
+				if selected[descendant.hash] {
+					continue
+				}
+
+				rate, ok := modified[descendant.hash]
+				if !ok {
+					rate = descendant.ancestorRate()
+				}
+				rate = feeRate{fee: rate.fee - member.fee, size: rate.size - member.size}
+				modified[descendant.hash] = rate
+				heap.Push(&candidates, packageCandidate{entry: descendant, rate: rate})
+			}
+		}
+	}
+
+	return transactions
+}
+
+// appendPackage appends entry to the transactions after the ancestors of it
+// found in the package, marking them as selected.
+func appendPackage(transactions []*blockchain.Transaction, entry *mempoolEntry, pkg map[string]*mempoolEntry, selected map[string]bool) []*blockchain.Transaction {
+	for _, input := range entry.tx.Inputs {
+		parent, ok := pkg[hex.EncodeToString(input.PreviousTxHash)]
+		if ok && !selected[parent.hash] {
+			transactions = appendPackage(transactions, parent, pkg, selected)
+		}
+	}
+
+	selected[entry.hash] = true
+	return append(transactions, entry.tx)
+}
+
+// ancestors returns the pending transactions the entry depends on, skipping
+// the ones in exclude.
+func (pool *Mempool) ancestors(entry *mempoolEntry, exclude map[string]bool) map[string]*mempoolEntry {
+	ancestors := make(map[string]*mempoolEntry)
+
+	stack := []*mempoolEntry{entry}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for hash, parent := range current.parents {
+			if _, ok := ancestors[hash]; ok || exclude[hash] {
+				continue
+			}
+			ancestors[hash] = parent
+			stack = append(stack, parent)
+		}
+	}
+
+	return ancestors
+}
+
+// updateAncestorTotals sums the entry and its pending ancestors again.
+func (pool *Mempool) updateAncestorTotals(entry *mempoolEntry) {
+	rate := entry.rate()
+	for _, ancestor := range pool.ancestors(entry, nil) {
+		rate = rate.add(ancestor)
+	}
+
+	entry.ancestorFee, entry.ancestorSize = rate.fee, rate.size
+}
+
+// descendants returns the pending transactions spending from the entries.
+func descendants(entries []*mempoolEntry) map[string]*mempoolEntry {
+	descendants := make(map[string]*mempoolEntry)
+
+	stack := append([]*mempoolEntry{}, entries...)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for hash, child := range current.children {
+			if _, ok := descendants[hash]; ok {
+				continue
+			}
+			descendants[hash] = child
+			stack = append(stack, child)
+		}
+	}
+
+	return descendants
+}
+
+// lookup returns the output of a pending transaction, or the one held by the
+// chain when the output was not created by a pending transaction.
+func (pool *Mempool) lookup(outPoint types.OutPoint) (*UTXO, error) {
+	entry, ok := pool.transactions[outPoint.TxHash]
+	if !ok {
+		return pool.chain.utxoStore.Get(outPoint)
+	}
+
+	if int(outPoint.Index) >= len(entry.tx.Outputs) {
+		return nil, fmt.Errorf("could not find utxo %s", outPoint)
+	}
+
+	return outputUTXO(entry.tx, outPoint.Hash(), int(outPoint.Index)), nil
+}
+
+// newEntry checks the transaction against the chain and the outputs returned
+// by lookup.
+func (pool *Mempool) newEntry(transaction *blockchain.Transaction, lookup func(types.OutPoint) (*UTXO, error)) (*mempoolEntry, error) {
+	hash := hex.EncodeToString(types.HashTransaction(transaction))
+	if _, ok := pool.transactions[hash]; ok {
+		return nil, ErrTxAlreadyKnown
+	}
+
+	fee, err := pool.chain.validateSpend(transaction, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mempoolEntry{
+		tx:    transaction,
+		hash:  hash,
+		fee:   fee,
+		size:  proto.Size(transaction),
+		added: time.Now(),
+	}, nil
+}
+
+// Add admits the transaction if it is valid on top of the current tip and the
+// pending transactions, and does not spend an output already spent by another
+// pending transaction, unless replace-by-fee is enabled and it outbids those
+// transactions. When the pool is full, entries with a lower fee rate than the
+// transaction and its pending ancestors are evicted to make room.
+func (pool *Mempool) Add(transaction *blockchain.Transaction) error {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	defer pool.publish()
+
+	entry, err := pool.newEntry(transaction, pool.lookup)
+	if err != nil {
+		return err
+	}
+
+	return pool.admit(entry, pool.ancestorRate(entry), nil)
+}
+
+// ancestorRate returns the fee rate of the entry together with its pending
+// ancestors.
+func (pool *Mempool) ancestorRate(entry *mempoolEntry) feeRate {
+	rate := entry.rate()
+	for _, ancestor := range pool.pendingAncestors(entry) {
+		rate = rate.add(ancestor)
+	}
+
+	return rate
+}
+
+// AddPackage admits the transactions, ordered parents first, all together or
+// not at all. When the pool is full, the package competes for room with the
+// fee rate of the whole package, so a child can pay for a parent that would
+// not be admitted on its own. Transactions already pending are skipped.
+func (pool *Mempool) AddPackage(transactions []*blockchain.Transaction) error {
+	if len(transactions) == 0 {
+		return fmt.Errorf("package is empty")
+	}
+	if len(transactions) > maxAncestors {
+		return fmt.Errorf("package of %d transactions exceeds the limit of %d", len(transactions), maxAncestors)
+	}
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	defer pool.publish()
+
+	var (
+		view    = newUTXOView(pool.lookup)
+		entries = []*mempoolEntry{}
+		members = make(map[string]bool)
+		rate    feeRate
+	)
+
+	for i, tx := range transactions {
+		entry, err := pool.newEntry(tx, view.Get)
+		if err == ErrTxAlreadyKnown {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("package transaction %d: %w", i, err)
+		}
+
+		view.apply(tx)
+		entries = append(entries, entry)
+		members[entry.hash] = true
+		rate = rate.add(entry)
+	}
+
+	if len(entries) == 0 {
+		return ErrTxAlreadyKnown
+	}
+
+	pool.journal = &mempoolJournal{counters: pool.removed, events: len(pool.events)}
+	defer func() {
+		pool.journal = nil
+	}()
+
+	for i, entry := range entries {
+		if err := pool.admit(entry, rate, members); err != nil {
+			pool.rollback()
+			return fmt.Errorf("package transaction %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (pool *Mempool) rollback() {
+	journal := pool.journal
+	pool.journal = nil
+
+	for i := len(journal.inserted) - 1; i >= 0; i-- {
+		pool.remove(journal.inserted[i], RemovedInvalid)
+	}
+	for _, entry := range journal.removed {
+		if _, ok := pool.transactions[entry.hash]; !ok {
+			pool.insert(entry)
+		}
+	}
+
+	pool.removed = journal.counters
+	pool.events = pool.events[:journal.events]
+}
+
+// pendingAncestors returns the pending transactions the entry, which is not
+// in the pool yet, depends on.
+func (pool *Mempool) pendingAncestors(entry *mempoolEntry) map[string]*mempoolEntry {
+	parents := make(map[string]*mempoolEntry)
+	for _, input := range entry.tx.Inputs {
+		if parent, ok := pool.transactions[hex.EncodeToString(input.PreviousTxHash)]; ok {
+			parents[parent.hash] = parent
+		}
+	}
+
+	return pool.ancestors(&mempoolEntry{parents: parents}, nil)
+}
+
+// admit inserts the entry once it is clear of conflicts and there is room for
+// it. Room is made by evicting entries with a lower fee rate than rate, the
+// entries in protect are never evicted.
+func (pool *Mempool) admit(entry *mempoolEntry, rate feeRate, protect map[string]bool) error {
+	if entry.evidence() {
+		address := offender(entry.tx.Evidence)
+		if other, ok := pool.convicted[address]; ok {
+			return fmt.Errorf("validator %s is already convicted by pending transaction %s", address, other)
+		}
+	}
+
+	ancestors := pool.pendingAncestors(entry)
+	if len(ancestors) >= maxAncestors {
+		return fmt.Errorf("transaction has more than %d pending ancestors", maxAncestors)
+	}
+
+	replaced, err := pool.conflicts(entry)
+	if err != nil {
+		return err
+	}
+
+	skip := make(map[string]bool)
+	for hash := range protect {
+		skip[hash] = true
+	}
+	for hash := range ancestors {
+		if _, ok := replaced[hash]; ok {
+			return fmt.Errorf("transaction spends an output of pending transaction %s it replaces", hash)
+		}
+		skip[hash] = true
+	}
+
+	if err := pool.makeRoom(entry, rate, replaced, skip); err != nil {
+		return err
+	}
+	pool.insert(entry)
+
+	return nil
+}
+
+// conflicts returns the pending transactions the entry replaces along with
+// their descendants, or an error when it spends an output of a pending
+// transaction it may not replace.
+func (pool *Mempool) conflicts(entry *mempoolEntry) (map[string]*mempoolEntry, error) {
+	direct := []*mempoolEntry{}
+	seen := make(map[string]bool)
+
+	for _, input := range entry.tx.Inputs {
+		outPoint := types.SpentOutPoint(input)
+		other, ok := pool.spends[outPoint]
+		if !ok || seen[other] {
+			continue
+		}
+		if !pool.config.ReplaceByFee {
+			return nil, fmt.Errorf("output %s is already spent by pending transaction %s", outPoint, other)
+		}
+
+		seen[other] = true
+		direct = append(direct, pool.transactions[other])
+	}
+
+	for _, other := range direct {
+		if entry.rate().compare(other.rate()) <= 0 {
+			return nil, fmt.Errorf("replacement does not pay a higher fee rate than pending transaction %s", other.hash)
+		}
+	}
+
+	replaced := descendants(direct)
+	for _, other := range direct {
+		replaced[other.hash] = other
+	}
+
+	var fees int64
+	for _, other := range replaced {
+		fees += other.fee
+	}
+
+	if len(replaced) > 0 && entry.fee <= fees {
+		return nil, fmt.Errorf("replacement fee (%d) does not exceed the fees of the replaced transactions (%d)", entry.fee, fees)
+	}
+
+	return replaced, nil
+}
+
+// makeRoom removes the replaced entries and evicts entries until the entry
+// fits. Entries without pending children are evicted first, worst fee rate
+// first, so a parent is never evicted while a child paying for it stays. It
+// fails when an entry that would have to go pays at least rate.
+func (pool *Mempool) makeRoom(entry *mempoolEntry, rate feeRate, replaced map[string]*mempoolEntry, protect map[string]bool) error {
+	if entry.size > pool.config.MaxBytes {
+		return fmt.Errorf("transaction of %d bytes exceeds the mempool size of %d bytes", entry.size, pool.config.MaxBytes)
+	}
+
+	size := pool.size + entry.size
+	gone := make(map[string]bool)
+	for hash, other := range replaced {
+		size -= other.size
+		gone[hash] = true
+	}
+
+	evicted := []*mempoolEntry{}
+	for size > pool.config.MaxBytes {
+		var worst *mempoolEntry
+		for i := len(pool.byFeeRate) - 1; i >= 0; i-- {
+			candidate := pool.byFeeRate[i]
+			if gone[candidate.hash] || protect[candidate.hash] || candidate.evidence() || hasChildrenLeft(candidate, gone) {
+				continue
+			}
+			worst = candidate
+			break
+		}
+
+		if worst == nil || (!entry.evidence() && rate.compare(worst.rate()) <= 0) {
+			return ErrMempoolFull
+		}
+		size -= worst.size
+		gone[worst.hash] = true
+		evicted = append(evicted, worst)
+	}
+
+	for _, other := range replaced {
+		pool.remove(other, RemovedReplaced)
+	}
+	for _, worst := range evicted {
+		pool.remove(worst, RemovedEvicted)
+	}
+
+	return nil
+}
+
+func hasChildrenLeft(entry *mempoolEntry, gone map[string]bool) bool {
+	for hash := range entry.children {
+		if !gone[hash] {
+			return true
+		}
+	}
+	return false
+}
+
+func (pool *Mempool) insert(entry *mempoolEntry) {
+	i := sort.Search(len(pool.byFeeRate), func(i int) bool {
+		return entry.betterThan(pool.byFeeRate[i])
+	})
+	pool.byFeeRate = append(pool.byFeeRate, nil)
+	copy(pool.byFeeRate[i+1:], pool.byFeeRate[i:])
+	pool.byFeeRate[i] = entry
+
+	entry.parents = make(map[string]*mempoolEntry)
+	entry.children = make(map[string]*mempoolEntry)
+
+	if entry.evidence() {
+		pool.convicted[offender(entry.tx.Evidence)] = entry.hash
+	}
+	for _, input := range entry.tx.Inputs {
+		pool.spends[types.SpentOutPoint(input)] = entry.hash
+
+		if parent, ok := pool.transactions[hex.EncodeToString(input.PreviousTxHash)]; ok {
+			entry.parents[parent.hash] = parent
+			parent.children[entry.hash] = entry
+		}
+	}
+
+	// the children of a transaction returning to the pool may already be
+	// pending
+	hash := types.HashTransaction(entry.tx)
+	for index := range entry.tx.Outputs {
+		if child, ok := pool.spends[types.NewOutPoint(hash, uint32(index))]; ok {
+			entry.children[child] = pool.transactions[child]
+			pool.transactions[child].parents[entry.hash] = entry
+		}
+	}
+
+	pool.transactions[entry.hash] = entry
+	pool.size += entry.size
+	pool.events = append(pool.events, entry.event(MempoolAdded, 0))
+
+	pool.updateAncestorTotals(entry)
+	for _, descendant := range descendants([]*mempoolEntry{entry}) {
+		pool.updateAncestorTotals(descendant)
+	}
+
+	if pool.journal != nil {
+		pool.journal.inserted = append(pool.journal.inserted, entry)
+	}
+}
+
+// remove drops the entry and, unless it was included in a block, its
+// descendants for the same reason.
+func (pool *Mempool) remove(entry *mempoolEntry, reason RemovalReason) {
+	if _, ok := pool.transactions[entry.hash]; !ok {
+		return
+	}
+
+	if reason != RemovedIncluded {
+		for _, child := range entry.children {
+			pool.remove(child, reason)
+		}
+	}
+
+	for _, parent := range entry.parents {
+		delete(parent.children, entry.hash)
+	}
+	// only the children of an entry included in a block are left
+	children := make([]*mempoolEntry, 0, len(entry.children))
+	for _, child := range entry.children {
+		delete(child.parents, entry.hash)
+		children = append(children, child)
+	}
+
+	i := sort.Search(len(pool.byFeeRate), func(i int) bool {
+		return !pool.byFeeRate[i].betterThan(entry)
+	})
+	pool.byFeeRate = append(pool.byFeeRate[:i], pool.byFeeRate[i+1:]...)
+
+	if entry.evidence() {
+		delete(pool.convicted, offender(entry.tx.Evidence))
+	}
+	for _, input := range entry.tx.Inputs {
+		delete(pool.spends, types.SpentOutPoint(input))
+	}
+	delete(pool.transactions, entry.hash)
+	pool.size -= entry.size
+	pool.removed[reason]++
+
+	for _, child := range children {
+		pool.updateAncestorTotals(child)
+	}
+	for _, descendant := range descendants(children) {
+		pool.updateAncestorTotals(descendant)
+	}
+	pool.events = append(pool.events, entry.event(MempoolRemoved, reason))
+
+	if pool.journal != nil {
+		pool.journal.removed = append(pool.journal.removed, entry)
+	}
+}
+
+// BlockConnected drops the transactions confirmed by the block and the ones
+// that are no longer valid on top of it.
+func (pool *Mempool) BlockConnected(block *blockchain.Block) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	defer pool.publish()
+
+	spent := make(map[types.OutPoint]bool)
+	for _, tx := range block.Transactions {
+		if entry, ok := pool.transactions[hex.EncodeToString(types.HashTransaction(tx))]; ok {
+			pool.remove(entry, RemovedIncluded)
+		}
+		if types.IsCoinbase(tx) {
+			continue
+		}
+		for _, input := range tx.Inputs {
+			spent[types.SpentOutPoint(input)] = true
+		}
+	}
+
+	for outPoint := range spent {
+		if hash, ok := pool.spends[outPoint]; ok {
+			pool.remove(pool.transactions[hash], RemovedConflict)
+		}
+	}
+
+	pool.revalidate(time.Now())
+}
+
+// BlockDisconnected returns the transactions of a block that left the main
+// chain to the pool, so they can be included in the new branch, and drops
+// the pending transactions that are no longer valid.
+func (pool *Mempool) BlockDisconnected(block *blockchain.Block) {
+	for _, tx := range block.Transactions {
+		if !types.IsCoinbase(tx) {
+			pool.Add(tx)
+		}
+	}
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	defer pool.publish()
+
+	pool.revalidate(time.Now())
+}
+
+// revalidate drops the expired transactions and the ones that are no longer
+// valid on top of the tip, along with their descendants.
+func (pool *Mempool) revalidate(now time.Time) {
+	entries := make([]*mempoolEntry, len(pool.byFeeRate))
+	copy(entries, pool.byFeeRate)
+
+	for _, entry := range entries {
+		if _, ok := pool.transactions[entry.hash]; !ok {
+			continue
+		}
+
+		if now.Sub(entry.added) > pool.config.TTL {
+			pool.remove(entry, RemovedExpired)
+			continue
+		}
+
+		if _, err := pool.chain.validateSpend(entry.tx, pool.lookup); err != nil {
+			pool.remove(entry, RemovedInvalid)
+		}
+	}
+}
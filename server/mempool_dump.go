@@ -0,0 +1,145 @@
+package server
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const mempoolDumpVersion = 1
+
+// Dump writes the pending transactions to path, parents first. Every entry is
+// laid out as added time (unix nanoseconds) | len(tx) | tx after a version
+// byte. The file is replaced atomically.
+func (pool *Mempool) Dump(path string) error {
+	pool.lock.RLock()
+	transactions := pool.selectPackages(math.MaxInt)
+	added := make([]time.Time, len(transactions))
+	for i, tx := range transactions {
+		added[i] = pool.transactions[hex.EncodeToString(types.HashTransaction(tx))].added
+	}
+	pool.lock.RUnlock()
+
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	writer := bufio.NewWriter(file)
+	writer.WriteByte(mempoolDumpVersion)
+
+	header := make([]byte, 12)
+	for i, tx := range transactions {
+		b, err := proto.Marshal(tx)
+		if err != nil {
+			file.Close()
+			return err
+		}
+
+		binary.LittleEndian.PutUint64(header[0:8], uint64(added[i].UnixNano()))
+		binary.LittleEndian.PutUint32(header[8:12], uint32(len(b)))
+		writer.Write(header)
+		writer.Write(b)
+	}
+
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	return syncDir(filepath.Dir(path))
+}
+
+// Load admits the transactions dumped to path, checking each of them against
+// the current tip as Add does. Transactions that are no longer valid or have
+// expired are skipped. A missing file loads nothing.
+func (pool *Mempool) Load(path string) (int, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	version, err := reader.ReadByte()
+	if err == io.EOF {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if version != mempoolDumpVersion {
+		return 0, fmt.Errorf("unknown mempool dump version %d", version)
+	}
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	defer pool.publish()
+
+	var (
+		loaded int
+		now    = time.Now()
+		header = make([]byte, 12)
+	)
+
+	for {
+		if _, err := io.ReadFull(reader, header); err == io.EOF {
+			return loaded, nil
+		} else if err != nil {
+			return loaded, fmt.Errorf("torn mempool dump entry: %w", err)
+		}
+
+		added := time.Unix(0, int64(binary.LittleEndian.Uint64(header[0:8])))
+		b := make([]byte, binary.LittleEndian.Uint32(header[8:12]))
+		if _, err := io.ReadFull(reader, b); err != nil {
+			return loaded, fmt.Errorf("torn mempool dump entry: %w", err)
+		}
+
+		tx := &blockchain.Transaction{}
+		if err := proto.Unmarshal(b, tx); err != nil {
+			return loaded, err
+		}
+
+		if now.Sub(added) > pool.config.TTL {
+			continue
+		}
+
+		entry, err := pool.newEntry(tx, pool.lookup)
+		if err != nil {
+			continue
+		}
+		entry.added = added
+
+		if err := pool.admit(entry, pool.ancestorRate(entry), nil); err != nil {
+			continue
+		}
+		loaded++
+	}
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"time"
+
+	blockchain "github.com/blockchain/proto"
+)
+
+type MempoolEventType int
+
+const (
+	MempoolAdded MempoolEventType = iota
+	MempoolRemoved
+)
+
+type MempoolEvent struct {
+	Type        MempoolEventType
+	Hash        string
+	Transaction *blockchain.Transaction
+	Fee         int64
+	Size        int
+	// Reason is only set for MempoolRemoved events.
+	Reason RemovalReason
+}
+
+type MempoolInfo struct {
+	Count    int
+	Bytes    int
+	MaxBytes int
+	// MinFee and MinSize are the fee and size of the pending transaction with
+	// the worst fee rate.
+	MinFee  int64
+	MinSize int
+	Removed map[RemovalReason]uint64
+}
+
+type MempoolEntry struct {
+	Transaction  *blockchain.Transaction
+	Fee          int64
+	Size         int
+	Added        time.Time
+	Parents      []string
+	Children     []string
+	AncestorFee  int64
+	AncestorSize int
+}
+
+type mempoolSubscriber struct {
+	events chan MempoolEvent
+}
+
+func (pool *Mempool) Info() *MempoolInfo {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	info := &MempoolInfo{
+		Count:    len(pool.transactions),
+		Bytes:    pool.size,
+		MaxBytes: pool.config.MaxBytes,
+		Removed:  make(map[RemovalReason]uint64),
+	}
+
+	if len(pool.byFeeRate) > 0 {
+		worst := pool.byFeeRate[len(pool.byFeeRate)-1]
+		info.MinFee, info.MinSize = worst.fee, worst.size
+	}
+
+	for reason, count := range pool.removed {
+		info.Removed[RemovalReason(reason)] = count
+	}
+
+	return info
+}
+
+// Entry returns the pending transaction with the given hex encoded hash.
+func (pool *Mempool) Entry(hash string) (*MempoolEntry, bool) {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	entry, ok := pool.transactions[hash]
+	if !ok {
+		return nil, false
+	}
+
+	rate := pool.ancestorRate(entry)
+	info := &MempoolEntry{
+		Transaction:  entry.tx,
+		Fee:          entry.fee,
+		Size:         entry.size,
+		Added:        entry.added,
+		Parents:      []string{},
+		Children:     []string{},
+		AncestorFee:  rate.fee,
+		AncestorSize: rate.size,
+	}
+	for hash := range entry.parents {
+		info.Parents = append(info.Parents, hash)
+	}
+	for hash := range entry.children {
+		info.Children = append(info.Children, hash)
+	}
+
+	return info, true
+}
+
+// Subscribe returns a channel receiving every transaction added to or removed
+// from the pool, and a function to cancel the subscription. The channel is
+// closed on cancel, or when the subscriber falls more than buffer events
+// behind.
+func (pool *Mempool) Subscribe(buffer int) (<-chan MempoolEvent, func()) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	subscriber := &mempoolSubscriber{events: make(chan MempoolEvent, buffer)}
+	pool.subscribers[subscriber] = struct{}{}
+
+	cancel := func() {
+		pool.lock.Lock()
+		defer pool.lock.Unlock()
+
+		pool.unsubscribe(subscriber)
+	}
+
+	return subscriber.events, cancel
+}
+
+func (pool *Mempool) unsubscribe(subscriber *mempoolSubscriber) {
+	if _, ok := pool.subscribers[subscriber]; ok {
+		delete(pool.subscribers, subscriber)
+		close(subscriber.events)
+	}
+}
+
+// publish hands the events recorded since the last call to the subscribers.
+// It must be called with the pool locked, once the changes are final.
+func (pool *Mempool) publish() {
+	for _, event := range pool.events {
+		for subscriber := range pool.subscribers {
+			select {
+			case subscriber.events <- event:
+			default:
+				pool.unsubscribe(subscriber)
+			}
+		}
+	}
+	pool.events = pool.events[:0]
+}
+
+func (entry *mempoolEntry) event(eventType MempoolEventType, reason RemovalReason) MempoolEvent {
+	return MempoolEvent{
+		Type:        eventType,
+		Hash:        entry.hash,
+		Transaction: entry.tx,
+		Fee:         entry.fee,
+		Size:        entry.size,
+		Reason:      reason,
+	}
+}
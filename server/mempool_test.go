@@ -0,0 +1,464 @@
+package server
+
+import (
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/blockchain/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMempoolAdmission(t *testing.T) {
+	var (
+		chain  = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool   = NewMempool(chain, MempoolConfig{})
+		tx     = genesisSpend(t, chain, 100)
+		double = genesisSpend(t, chain, 200)
+	)
+
+	require.Nil(t, pool.Add(tx))
+	require.ErrorIs(t, pool.Add(tx), ErrTxAlreadyKnown)
+	require.NotNil(t, pool.Add(double))
+
+	pool.Remove(tx, RemovedInvalid)
+	require.Nil(t, pool.Add(double))
+	require.False(t, pool.Has(tx))
+	require.True(t, pool.Has(double))
+
+	pool.Remove(double, RemovedInvalid)
+	require.Equal(t, 0, pool.Len())
+	require.Nil(t, pool.Add(tx))
+}
+
+// fanOut mines a block splitting the genesis output into outputs of 100 paying
+// to a new key.
+func fanOut(t *testing.T, chain *Chain, n int) (*crypto.PrivateKey, []types.OutPoint) {
+	var (
+		genesisKey = crypto.NewPrivateKeyFromString(seed)
+		key        = crypto.GeneratePrivateKey()
+		outputs    = make([]*blockchain.TxOutput, n)
+	)
+
+	genesis, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	for i := range outputs {
+		outputs[i] = payTo(key, 100)
+	}
+	tx := signedTx([]spend{{genesisKey, outPoint(genesis.Transactions[0], 0)}}, outputs...)
+	require.Nil(t, mineBlock(chain, tx))
+
+	outPoints := make([]types.OutPoint, n)
+	for i := range outPoints {
+		outPoints[i] = outPoint(tx, uint32(i))
+	}
+
+	return key, outPoints
+}
+
+// blockSize is the room the transactions take in a block.
+func blockSize(transactions ...*blockchain.Transaction) int {
+	return proto.Size(&blockchain.Block{Transactions: transactions})
+}
+
+func TestMempoolFeeRateOrder(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool           = NewMempool(chain, MempoolConfig{})
+		key, outPoints = fanOut(t, chain, 3)
+		to             = crypto.GeneratePrivateKey()
+		low            = signedTx([]spend{{key, outPoints[0]}}, payTo(to, 99))
+		high           = signedTx([]spend{{key, outPoints[1]}}, payTo(to, 90))
+		medium         = signedTx([]spend{{key, outPoints[2]}}, payTo(to, 95))
+	)
+
+	require.Nil(t, pool.Add(low))
+	require.Nil(t, pool.Add(high))
+	require.Nil(t, pool.Add(medium))
+	require.Equal(t, proto.Size(low)+proto.Size(high)+proto.Size(medium), pool.Size())
+
+	require.Equal(t, []*blockchain.Transaction{high, medium, low}, pool.Select(1<<20))
+	require.Equal(t, []*blockchain.Transaction{high}, pool.Select(blockSize(high)))
+	require.Empty(t, pool.Select(0))
+	require.Equal(t, 3, pool.Len())
+}
+
+func TestMempoolEviction(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		key, outPoints = fanOut(t, chain, 5)
+		to             = crypto.GeneratePrivateKey()
+		// index 0 is left out of the encoding, spend the others so all
+		// transactions have the same size
+		low    = signedTx([]spend{{key, outPoints[1]}}, payTo(to, 99))
+		medium = signedTx([]spend{{key, outPoints[2]}}, payTo(to, 95))
+		high   = signedTx([]spend{{key, outPoints[3]}}, payTo(to, 90))
+		lowest = signedTx([]spend{{key, outPoints[4]}}, payTo(to, 100))
+		pool   = NewMempool(chain, MempoolConfig{MaxBytes: proto.Size(low) + proto.Size(medium)})
+	)
+
+	require.Nil(t, pool.Add(low))
+	require.Nil(t, pool.Add(medium))
+
+	require.Nil(t, pool.Add(high))
+	require.False(t, pool.Has(low))
+	require.True(t, pool.Has(medium))
+	require.True(t, pool.Has(high))
+	require.LessOrEqual(t, pool.Size(), proto.Size(low)+proto.Size(medium))
+
+	require.ErrorIs(t, pool.Add(lowest), ErrMempoolFull)
+	require.Equal(t, 2, pool.Len())
+
+	// the evicted outputs can be spent again
+	require.Nil(t, pool.Add(signedTx([]spend{{key, outPoints[1]}}, payTo(to, 50))))
+	require.False(t, pool.Has(medium))
+}
+
+func TestMempoolRevalidation(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool           = NewMempool(chain, MempoolConfig{})
+		key, outPoints = fanOut(t, chain, 3)
+		to             = crypto.GeneratePrivateKey()
+		included       = signedTx([]spend{{key, outPoints[1]}}, payTo(to, 90))
+		pending        = signedTx([]spend{{key, outPoints[2]}}, payTo(to, 90))
+		conflict       = signedTx([]spend{{key, outPoints[2]}}, payTo(to, 80))
+	)
+	chain.Subscribe(pool)
+
+	require.Nil(t, pool.Add(included))
+	require.Nil(t, pool.Add(pending))
+
+	require.Nil(t, mineBlock(chain, included, conflict))
+	require.Equal(t, 0, pool.Len())
+	require.Equal(t, uint64(1), pool.Removed(RemovedIncluded))
+	require.Equal(t, uint64(1), pool.Removed(RemovedConflict))
+
+	require.Nil(t, chain.DisconnectTip())
+	require.True(t, pool.Has(included))
+	require.True(t, pool.Has(conflict))
+	require.False(t, pool.Has(pending))
+
+	// the fan out goes back to the pool and the transactions spending it stay
+	// as its children
+	require.Nil(t, chain.DisconnectTip())
+	require.Equal(t, 3, pool.Len())
+	require.True(t, pool.Has(included))
+	require.True(t, pool.Has(conflict))
+	require.Equal(t, uint64(0), pool.Removed(RemovedInvalid))
+
+	// a block spending the genesis output otherwise invalidates all of them
+	genesisKey := crypto.NewPrivateKeyFromString(seed)
+	genesis, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+	require.Nil(t, mineBlock(chain, signedTx([]spend{{genesisKey, outPoint(genesis.Transactions[0], 0)}}, payTo(to, 1000))))
+	require.Equal(t, 0, pool.Len())
+	require.Equal(t, uint64(4), pool.Removed(RemovedConflict))
+}
+
+func TestMempoolExpiry(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool           = NewMempool(chain, MempoolConfig{TTL: time.Minute})
+		key, outPoints = fanOut(t, chain, 3)
+		to             = crypto.GeneratePrivateKey()
+		old            = signedTx([]spend{{key, outPoints[1]}}, payTo(to, 90))
+		recent         = signedTx([]spend{{key, outPoints[2]}}, payTo(to, 90))
+	)
+	chain.Subscribe(pool)
+
+	require.Nil(t, pool.Add(old))
+	require.Nil(t, pool.Add(recent))
+	pool.transactions[hex.EncodeToString(types.HashTransaction(old))].added = time.Now().Add(-2 * time.Minute)
+
+	require.Nil(t, mineBlock(chain))
+	require.False(t, pool.Has(old))
+	require.True(t, pool.Has(recent))
+	require.Equal(t, uint64(1), pool.Removed(RemovedExpired))
+}
+
+func TestMempoolReplaceByFee(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool           = NewMempool(chain, MempoolConfig{ReplaceByFee: true})
+		key, outPoints = fanOut(t, chain, 3)
+		to             = crypto.GeneratePrivateKey()
+		first          = signedTx([]spend{{key, outPoints[1]}}, payTo(to, 95))
+		second         = signedTx([]spend{{key, outPoints[2]}}, payTo(to, 95))
+		cheaper        = signedTx([]spend{{key, outPoints[1]}}, payTo(to, 96))
+		bump           = signedTx([]spend{{key, outPoints[1]}}, payTo(to, 90))
+		both           = signedTx([]spend{{key, outPoints[1]}, {key, outPoints[2]}}, payTo(to, 184))
+		outbid         = signedTx([]spend{{key, outPoints[1]}, {key, outPoints[2]}}, payTo(to, 170))
+	)
+
+	require.Nil(t, pool.Add(first))
+	require.Nil(t, pool.Add(second))
+
+	require.NotNil(t, pool.Add(cheaper))
+	require.True(t, pool.Has(first))
+
+	require.Nil(t, pool.Add(bump))
+	require.False(t, pool.Has(first))
+	require.True(t, pool.Has(bump))
+	require.Equal(t, uint64(1), pool.Removed(RemovedReplaced))
+
+	// a higher total fee is not enough when the fee rate is lower
+	err := pool.Add(both)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "fee rate")
+	require.Nil(t, pool.Add(outbid))
+	require.Equal(t, 1, pool.Len())
+	require.True(t, pool.Has(outbid))
+	require.Equal(t, uint64(3), pool.Removed(RemovedReplaced))
+}
+
+func TestMempoolReplaceByFeeDisabled(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool           = NewMempool(chain, MempoolConfig{})
+		key, outPoints = fanOut(t, chain, 2)
+		to             = crypto.GeneratePrivateKey()
+	)
+
+	require.Nil(t, pool.Add(signedTx([]spend{{key, outPoints[1]}}, payTo(to, 95))))
+	require.NotNil(t, pool.Add(signedTx([]spend{{key, outPoints[1]}}, payTo(to, 50))))
+	require.Equal(t, uint64(0), pool.Removed(RemovedReplaced))
+}
+
+func TestMempoolChildPaysForParent(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool           = NewMempool(chain, MempoolConfig{})
+		key, outPoints = fanOut(t, chain, 3)
+		to             = crypto.GeneratePrivateKey()
+		parent         = signedTx([]spend{{key, outPoints[1]}}, payTo(key, 99))
+		child          = signedTx([]spend{{key, outPoint(parent, 0)}}, payTo(to, 49))
+		medium         = signedTx([]spend{{key, outPoints[2]}}, payTo(to, 90))
+	)
+	chain.Subscribe(pool)
+
+	require.Nil(t, pool.Add(parent))
+	require.Nil(t, pool.Add(child))
+	require.Nil(t, pool.Add(medium))
+
+	require.Equal(t, []*blockchain.Transaction{parent, child, medium}, pool.Select(1<<20))
+	require.Equal(t, []*blockchain.Transaction{medium}, pool.Select(blockSize(medium)))
+	require.Equal(t, []*blockchain.Transaction{parent, child}, pool.Select(blockSize(parent, child)))
+
+	require.Nil(t, mineBlock(chain, pool.Select(blockSize(parent, child))...))
+	require.Equal(t, 1, pool.Len())
+	require.True(t, pool.Has(medium))
+
+	// the parent leaves the chain, the child goes back to spending from it
+	require.Nil(t, chain.DisconnectTip())
+	require.Equal(t, []*blockchain.Transaction{parent, child, medium}, pool.Select(1<<20))
+}
+
+func TestMempoolSelectRanksModifiedPackages(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool           = NewMempool(chain, MempoolConfig{})
+		key, outPoints = fanOut(t, chain, 3)
+		to             = crypto.GeneratePrivateKey()
+		parent         = signedTx([]spend{{key, outPoints[0]}}, payTo(key, 50), payTo(key, 49))
+		high           = signedTx([]spend{{key, outPoint(parent, 0)}}, payTo(to, 20))
+		medium         = signedTx([]spend{{key, outPoint(parent, 1)}}, payTo(to, 29))
+		single         = signedTx([]spend{{key, outPoints[1]}}, payTo(to, 75))
+		low            = signedTx([]spend{{key, outPoints[2]}}, payTo(to, 88))
+	)
+	chain.Subscribe(pool)
+
+	for _, tx := range []*blockchain.Transaction{parent, high, medium, single, low} {
+		require.Nil(t, pool.Add(tx))
+	}
+
+	entry := func(tx *blockchain.Transaction) *mempoolEntry {
+		return pool.transactions[hex.EncodeToString(types.HashTransaction(tx))]
+	}
+	require.Equal(t, int64(31), entry(high).ancestorFee)
+	require.Equal(t, proto.Size(parent)+proto.Size(high), entry(high).ancestorSize)
+
+	// once the parent is paid for by the high child, the medium child is
+	// ranked on its own fee rate and goes before the low one
+	require.Equal(t, []*blockchain.Transaction{single, parent, high, medium, low}, pool.Select(1<<20))
+
+	require.Nil(t, mineBlock(chain, parent))
+	require.Equal(t, int64(30), entry(high).ancestorFee)
+	require.Equal(t, proto.Size(high), entry(high).ancestorSize)
+	require.Equal(t, []*blockchain.Transaction{high, single, medium, low}, pool.Select(1<<20))
+}
+
+func TestMempoolSelectFitsBlock(t *testing.T) {
+	var (
+		chain = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool  = NewMempool(chain, MempoolConfig{})
+		key   = crypto.GeneratePrivateKey()
+	)
+
+	// small transactions spend most of a block on their prefixes
+	for pool.Size() <= maxBlockSize {
+		tx := &blockchain.Transaction{
+			Version: 1,
+			Inputs:  []*blockchain.TxInput{{PreviousTxHash: util.RandomHash()}},
+			Outputs: []*blockchain.TxOutput{payTo(key, 1)},
+		}
+		pool.insert(&mempoolEntry{
+			tx:    tx,
+			hash:  hex.EncodeToString(types.HashTransaction(tx)),
+			fee:   1,
+			size:  proto.Size(tx),
+			added: time.Now(),
+		})
+	}
+
+	block, err := chain.NewBlock(nil, pool.Select(maxBlockSize-blockOverhead))
+	require.Nil(t, err)
+	types.SignBlock(key, block)
+	require.Less(t, len(block.Transactions), pool.Len())
+	require.LessOrEqual(t, proto.Size(block), maxBlockSize)
+}
+
+func TestMempoolReplaceParent(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool           = NewMempool(chain, MempoolConfig{ReplaceByFee: true})
+		key, outPoints = fanOut(t, chain, 2)
+		to             = crypto.GeneratePrivateKey()
+		parent         = signedTx([]spend{{key, outPoints[1]}}, payTo(key, 95))
+		child          = signedTx([]spend{{key, outPoint(parent, 0)}}, payTo(to, 85))
+	)
+
+	require.Nil(t, pool.Add(parent))
+	require.Nil(t, pool.Add(child))
+
+	// the replacement has to pay for the child it evicts as well
+	require.NotNil(t, pool.Add(signedTx([]spend{{key, outPoints[1]}}, payTo(to, 90))))
+	require.Nil(t, pool.Add(signedTx([]spend{{key, outPoints[1]}}, payTo(to, 80))))
+	require.False(t, pool.Has(parent))
+	require.False(t, pool.Has(child))
+	require.Equal(t, uint64(2), pool.Removed(RemovedReplaced))
+}
+
+func TestMempoolAddPackage(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		key, outPoints = fanOut(t, chain, 4)
+		to             = crypto.GeneratePrivateKey()
+		first          = signedTx([]spend{{key, outPoints[1]}}, payTo(to, 90))
+		second         = signedTx([]spend{{key, outPoints[2]}}, payTo(to, 90))
+		parent         = signedTx([]spend{{key, outPoints[3]}}, payTo(key, 99))
+		child          = signedTx([]spend{{key, outPoint(parent, 0)}}, payTo(to, 39))
+		pool           = NewMempool(chain, MempoolConfig{MaxBytes: proto.Size(first) + proto.Size(second)})
+	)
+
+	require.Nil(t, pool.Add(first))
+	require.Nil(t, pool.Add(second))
+	require.ErrorIs(t, pool.Add(parent), ErrMempoolFull)
+
+	// the child spends an output of a pending transaction, the parent is
+	// rolled back along with the eviction it caused
+	conflicting := signedTx([]spend{{key, outPoint(parent, 0)}, {key, outPoints[1]}}, payTo(to, 100))
+	require.NotNil(t, pool.AddPackage([]*blockchain.Transaction{parent, conflicting}))
+	require.True(t, pool.Has(first))
+	require.True(t, pool.Has(second))
+	require.False(t, pool.Has(parent))
+	require.Equal(t, uint64(0), pool.Removed(RemovedEvicted))
+
+	require.NotNil(t, pool.AddPackage([]*blockchain.Transaction{child, parent}))
+
+	require.Nil(t, pool.AddPackage([]*blockchain.Transaction{parent, child}))
+	require.True(t, pool.Has(parent))
+	require.True(t, pool.Has(child))
+	require.Equal(t, 2, pool.Len())
+	require.Equal(t, uint64(2), pool.Removed(RemovedEvicted))
+
+	require.ErrorIs(t, pool.AddPackage([]*blockchain.Transaction{parent, child}), ErrTxAlreadyKnown)
+}
+
+func TestMempoolDumpAndLoad(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool           = NewMempool(chain, MempoolConfig{})
+		key, outPoints = fanOut(t, chain, 4)
+		to             = crypto.GeneratePrivateKey()
+		parent         = signedTx([]spend{{key, outPoints[1]}}, payTo(key, 99))
+		child          = signedTx([]spend{{key, outPoint(parent, 0)}}, payTo(to, 49))
+		confirmed      = signedTx([]spend{{key, outPoints[2]}}, payTo(to, 90))
+		expired        = signedTx([]spend{{key, outPoints[3]}}, payTo(to, 90))
+		path           = filepath.Join(t.TempDir(), "mempool.dat")
+	)
+
+	for _, tx := range []*blockchain.Transaction{parent, child, confirmed, expired} {
+		require.Nil(t, pool.Add(tx))
+	}
+	pool.transactions[hex.EncodeToString(types.HashTransaction(expired))].added = time.Now().Add(-2 * defaultMempoolTTL)
+	added := pool.transactions[hex.EncodeToString(types.HashTransaction(parent))].added
+
+	require.Nil(t, pool.Dump(path))
+	require.Nil(t, mineBlock(chain, confirmed))
+
+	reloaded := NewMempool(chain, MempoolConfig{})
+	loaded, err := reloaded.Load(path)
+	require.Nil(t, err)
+	require.Equal(t, 2, loaded)
+	require.Equal(t, []*blockchain.Transaction{parent, child}, reloaded.Select(1<<20))
+	require.True(t, added.Equal(reloaded.transactions[hex.EncodeToString(types.HashTransaction(parent))].added))
+
+	loaded, err = NewMempool(chain, MempoolConfig{}).Load(filepath.Join(t.TempDir(), "missing.dat"))
+	require.Nil(t, err)
+	require.Equal(t, 0, loaded)
+}
+
+func TestMempoolSubscribe(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		pool           = NewMempool(chain, MempoolConfig{})
+		key, outPoints = fanOut(t, chain, 3)
+		to             = crypto.GeneratePrivateKey()
+		parent         = signedTx([]spend{{key, outPoints[1]}}, payTo(key, 99))
+		child          = signedTx([]spend{{key, outPoint(parent, 0)}}, payTo(to, 49))
+		conflicting    = signedTx([]spend{{key, outPoints[1]}}, payTo(to, 10))
+	)
+
+	events, cancel := pool.Subscribe(10)
+
+	require.Nil(t, pool.Add(parent))
+	require.Nil(t, pool.Add(child))
+	// a rejected package leaves no trace
+	require.NotNil(t, pool.AddPackage([]*blockchain.Transaction{signedTx([]spend{{key, outPoints[2]}}, payTo(to, 90)), conflicting}))
+	pool.Remove(parent, RemovedInvalid)
+
+	expected := []MempoolEvent{
+		{Type: MempoolAdded, Transaction: parent},
+		{Type: MempoolAdded, Transaction: child},
+		{Type: MempoolRemoved, Transaction: child, Reason: RemovedInvalid},
+		{Type: MempoolRemoved, Transaction: parent, Reason: RemovedInvalid},
+	}
+	for _, want := range expected {
+		event := <-events
+		require.Equal(t, want.Type, event.Type)
+		require.Equal(t, want.Transaction, event.Transaction)
+		require.Equal(t, hex.EncodeToString(types.HashTransaction(want.Transaction)), event.Hash)
+		require.Equal(t, want.Reason, event.Reason)
+	}
+	require.Len(t, events, 0)
+
+	cancel()
+	_, ok := <-events
+	require.False(t, ok)
+	cancel()
+
+	// a subscriber falling behind is dropped
+	events, _ = pool.Subscribe(1)
+	require.Nil(t, pool.Add(parent))
+	require.Nil(t, pool.Add(child))
+	<-events
+	_, ok = <-events
+	require.False(t, ok)
+}
@@ -0,0 +1,92 @@
+package server
+
+const genesisAmount = 1000
+
+// MonetaryPolicy describes how many units each block may mint in its coinbase.
+// The subsidy starts at InitialSubsidy and halves every HalvingInterval blocks,
+// but never drops below TailEmission. Once the total issuance, including the
+// genesis output, reaches MaxSupply no more units are minted.
+type MonetaryPolicy struct {
+	InitialSubsidy int64
+	// HalvingInterval of 0 disables halving.
+	HalvingInterval int
+	TailEmission    int64
+	// MaxSupply of 0 leaves the supply uncapped.
+	MaxSupply int64
+}
+
+var DefaultMonetaryPolicy = MonetaryPolicy{
+	InitialSubsidy:  50,
+	HalvingInterval: 210000,
+	MaxSupply:       21000000,
+}
+
+type Supply struct {
+	Height      int
+	Circulating int64
+	Scheduled   int64
+}
+
+func WithMonetaryPolicy(policy MonetaryPolicy) ChainOption {
+	return func(chain *Chain) {
+		chain.policy = policy
+	}
+}
+
+// Subsidy returns the amount the coinbase of the block at the given height may
+// mint on top of the fees of the block.
+func (policy MonetaryPolicy) Subsidy(height int) int64 {
+	if height <= 0 {
+		return 0
+	}
+
+	return policy.IssuedAt(height) - policy.IssuedAt(height-1)
+}
+
+// IssuedAt returns the number of units scheduled to exist once the block at the
+// given height is connected.
+func (policy MonetaryPolicy) IssuedAt(height int) int64 {
+	issued := int64(genesisAmount)
+
+	for era, start := 0, 1; start <= height; era++ {
+		subsidy := policy.eraSubsidy(era)
+		blocks := height - start + 1
+
+		// once the subsidy stops changing the rest of the range is a single era
+		final := policy.HalvingInterval == 0 || subsidy == policy.eraSubsidy(era+1)
+		if !final && blocks > policy.HalvingInterval {
+			blocks = policy.HalvingInterval
+		}
+
+		if policy.MaxSupply > 0 && subsidy > 0 && int64(blocks) > (policy.MaxSupply-issued)/subsidy {
+			return policy.MaxSupply
+		}
+		issued += subsidy * int64(blocks)
+
+		if final {
+			break
+		}
+		start += blocks
+	}
+
+	if policy.MaxSupply > 0 && issued > policy.MaxSupply {
+		return policy.MaxSupply
+	}
+
+	return issued
+}
+
+func (policy MonetaryPolicy) eraSubsidy(era int) int64 {
+	subsidy := policy.InitialSubsidy
+	if era >= 63 {
+		subsidy = 0
+	} else {
+		subsidy >>= era
+	}
+
+	if subsidy < policy.TailEmission {
+		subsidy = policy.TailEmission
+	}
+
+	return subsidy
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonetaryPolicyHalving(t *testing.T) {
+	policy := MonetaryPolicy{InitialSubsidy: 50, HalvingInterval: 10}
+
+	require.Equal(t, int64(0), policy.Subsidy(0))
+	require.Equal(t, int64(50), policy.Subsidy(1))
+	require.Equal(t, int64(50), policy.Subsidy(10))
+	require.Equal(t, int64(25), policy.Subsidy(11))
+	require.Equal(t, int64(12), policy.Subsidy(21))
+	require.Equal(t, int64(0), policy.Subsidy(1000))
+
+	require.Equal(t, int64(genesisAmount), policy.IssuedAt(0))
+	require.Equal(t, int64(genesisAmount+500+250+120), policy.IssuedAt(30))
+}
+
+func TestMonetaryPolicyTailEmission(t *testing.T) {
+	policy := MonetaryPolicy{InitialSubsidy: 50, HalvingInterval: 10, TailEmission: 10}
+
+	require.Equal(t, int64(12), policy.Subsidy(30))
+	require.Equal(t, int64(10), policy.Subsidy(31))
+	require.Equal(t, int64(10), policy.Subsidy(1000000))
+	require.Equal(t, int64(genesisAmount+500+250+120+10*(1000000-30)), policy.IssuedAt(1000000))
+}
+
+func TestMonetaryPolicyMaxSupply(t *testing.T) {
+	policy := MonetaryPolicy{InitialSubsidy: 50, MaxSupply: genesisAmount + 120}
+
+	require.Equal(t, int64(50), policy.Subsidy(2))
+	require.Equal(t, int64(20), policy.Subsidy(3))
+	require.Equal(t, int64(0), policy.Subsidy(4))
+	require.Equal(t, policy.MaxSupply, policy.IssuedAt(1<<30))
+}
+
+func TestCoinbaseFollowsMonetaryPolicy(t *testing.T) {
+	var (
+		policy    = MonetaryPolicy{InitialSubsidy: 40, HalvingInterval: 1}
+		chain     = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithMonetaryPolicy(policy))
+		validator = crypto.GeneratePrivateKey()
+		address   = validator.Public().Address().Bytes()
+	)
+
+	block, err := chain.NewBlock(address, nil)
+	require.Nil(t, err)
+	require.Equal(t, int64(40), block.Transactions[0].Outputs[0].Amount)
+	types.SignBlock(validator, block)
+	require.Nil(t, chain.AddBlock(block))
+
+	// the subsidy halved at height 2
+	block, err = chain.NewBlock(nil, []*blockchain.Transaction{types.NewCoinbaseTransaction(address, 40, 2)})
+	require.Nil(t, err)
+	types.SignBlock(validator, block)
+	require.NotNil(t, chain.AddBlock(block))
+
+	block, err = chain.NewBlock(address, nil)
+	require.Nil(t, err)
+	require.Equal(t, int64(20), block.Transactions[0].Outputs[0].Amount)
+	types.SignBlock(validator, block)
+	require.Nil(t, chain.AddBlock(block))
+
+	supply, err := chain.Supply()
+	require.Nil(t, err)
+	require.Equal(t, 2, supply.Height)
+	require.Equal(t, int64(genesisAmount+60), supply.Circulating)
+	require.Equal(t, int64(genesisAmount+60), supply.Scheduled)
+}
+
+func TestGenesisCommitsToMonetaryPolicy(t *testing.T) {
+	policy := MonetaryPolicy{InitialSubsidy: 100, HalvingInterval: 10}
+	require.NotEqual(t,
+		types.HashBlock(createGenesisBlock(Genesis{}, DefaultMonetaryPolicy)),
+		types.HashBlock(createGenesisBlock(Genesis{}, policy)),
+	)
+
+	blockStore, txStore, utxoStore := NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()
+	_, err := OpenChain(blockStore, txStore, utxoStore)
+	require.Nil(t, err)
+
+	// a node minting a different subsidy is on another network from the start
+	_, err = OpenChain(blockStore, txStore, utxoStore, WithMonetaryPolicy(policy))
+	var corruption *ChainCorruptionError
+	require.ErrorAs(t, err, &corruption)
+}
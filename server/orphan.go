@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/hex"
+	"sync"
+
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+)
+
+// maxOrphanBlocks bounds the blocks kept while their parent is missing.
+const maxOrphanBlocks = 128
+
+// orphanPool holds the blocks received before their parent, by the hash of
+// the parent, until the parent is connected. The oldest orphans are dropped
+// first once the pool is full.
+type orphanPool struct {
+	lock     sync.Mutex
+	blocks   map[string]*blockchain.Block
+	byParent map[string][]string
+	order    []string
+}
+
+func newOrphanPool() *orphanPool {
+	return &orphanPool{
+		blocks:   make(map[string]*blockchain.Block),
+		byParent: make(map[string][]string),
+	}
+}
+
+// Add keeps the block and reports whether it was not in the pool before.
+func (pool *orphanPool) Add(block *blockchain.Block) bool {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	hash := hex.EncodeToString(types.HashBlock(block))
+	if _, ok := pool.blocks[hash]; ok {
+		return false
+	}
+
+	if len(pool.order) == maxOrphanBlocks {
+		pool.remove(pool.order[0])
+	}
+
+	parent := hex.EncodeToString(block.Header.PreviousHash)
+	pool.blocks[hash] = block
+	pool.byParent[parent] = append(pool.byParent[parent], hash)
+	pool.order = append(pool.order, hash)
+
+	return true
+}
+
+// Children removes and returns the orphans of the given parent.
+func (pool *orphanPool) Children(parent string) []*blockchain.Block {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	// remove shifts the siblings in place, walk a copy of them
+	hashes := append([]string(nil), pool.byParent[parent]...)
+
+	children := []*blockchain.Block{}
+	for _, hash := range hashes {
+		block, ok := pool.blocks[hash]
+		if !ok {
+			continue
+		}
+		children = append(children, block)
+		pool.remove(hash)
+	}
+
+	return children
+}
+
+func (pool *orphanPool) Len() int {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	return len(pool.blocks)
+}
+
+func (pool *orphanPool) remove(hash string) {
+	block, ok := pool.blocks[hash]
+	if !ok {
+		return
+	}
+	delete(pool.blocks, hash)
+
+	parent := hex.EncodeToString(block.Header.PreviousHash)
+	siblings := pool.byParent[parent]
+	for i, sibling := range siblings {
+		if sibling == hash {
+			siblings = append(siblings[:i], siblings[i+1:]...)
+			break
+		}
+	}
+	if len(siblings) == 0 {
+		delete(pool.byParent, parent)
+	} else {
+		pool.byParent[parent] = siblings
+	}
+
+	for i, ordered := range pool.order {
+		if ordered == hash {
+			pool.order = append(pool.order[:i], pool.order[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/blockchain/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrphanPoolChildren(t *testing.T) {
+	var (
+		pool   = newOrphanPool()
+		parent = util.RandomHash()
+		other  = util.RandomBlock()
+	)
+
+	siblings := []*blockchain.Block{}
+	for i := 0; i < 3; i++ {
+		block := util.RandomBlock()
+		block.Header.PreviousHash = parent
+		types.SignBlock(crypto.GeneratePrivateKey(), block)
+		require.True(t, pool.Add(block))
+		siblings = append(siblings, block)
+	}
+	require.True(t, pool.Add(other))
+	require.False(t, pool.Add(siblings[0]))
+
+	require.ElementsMatch(t, siblings, pool.Children(hex.EncodeToString(parent)))
+	require.Equal(t, 1, pool.Len())
+	require.Empty(t, pool.Children(hex.EncodeToString(parent)))
+}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetBlockByHash(ctx context.Context, request *blockchain.GetBlockByHashRequest) (*blockchain.Block, error) {
+	block, err := server.chain.GetBlockByHash(request.Hash)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "block %s not found", hex.EncodeToString(request.Hash))
+	}
+
+	return block, nil
+}
+
+func (server *Server) GetBlockByHeight(ctx context.Context, request *blockchain.GetBlockByHeightRequest) (*blockchain.Block, error) {
+	if request.Height < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid height %d", request.Height)
+	}
+
+	block, err := server.chain.GetBlockByHeight(int(request.Height))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "no block at height %d", request.Height)
+	}
+
+	return block, nil
+}
+
+func (server *Server) GetTransaction(ctx context.Context, request *blockchain.GetTransactionRequest) (*blockchain.TransactionInfo, error) {
+	tx, err := server.chain.GetTransaction(request.Hash)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "transaction %s not found", hex.EncodeToString(request.Hash))
+	}
+
+	fee, err := server.chain.TransactionFee(tx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &blockchain.TransactionInfo{
+		Transaction: tx,
+		Fee:         fee,
+	}, nil
+}
+
+func (server *Server) GetChainInfo(ctx context.Context, request *blockchain.GetChainInfoRequest) (*blockchain.ChainInfo, error) {
+	height := server.chain.Height()
+
+	tip, err := server.chain.GetHeaderByHeight(height)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	genesis, err := server.chain.GetHeaderByHeight(0)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	finalized := server.chain.Finalized()
+	info := &blockchain.ChainInfo{
+		Height:          int32(height),
+		TipHash:         types.HashHeader(tip),
+		GenesisHash:     types.HashHeader(genesis),
+		FinalizedHeight: int32(finalized.Height),
+		FinalizedHash:   types.HashHeader(finalized.Header),
+	}
+	if server.PrivateKey != nil {
+		info.ValidatorKey = server.PrivateKey.Public().Bytes()
+	}
+
+	return info, nil
+}
+
+func (server *Server) GetCommit(ctx context.Context, request *blockchain.GetCommitRequest) (*blockchain.Commit, error) {
+	hash := request.Hash
+	if len(hash) == 0 {
+		if int(request.Height) > server.chain.Finalized().Height {
+			return nil, status.Errorf(codes.NotFound, "block at height %d is not finalized", request.Height)
+		}
+
+		header, err := server.chain.GetHeaderByHeight(int(request.Height))
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		hash = types.HashHeader(header)
+	}
+
+	commit, err := server.chain.GetCommit(hash)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if commit == nil {
+		return nil, status.Errorf(codes.NotFound, "no commit for block %s", hex.EncodeToString(hash))
+	}
+
+	return commit, nil
+}
+
+func (server *Server) GetBalance(ctx context.Context, request *blockchain.AddressRequest) (*blockchain.Balance, error) {
+	if len(request.Address) != crypto.AddressLen {
+		return nil, status.Errorf(codes.InvalidArgument, "address should be %d bytes", crypto.AddressLen)
+	}
+
+	balance, err := server.chain.GetBalance(request.Address)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &blockchain.Balance{
+		Amount: balance,
+		Staked: server.chain.Stakes()[hex.EncodeToString(request.Address)],
+	}, nil
+}
+
+func (server *Server) ListUnspent(ctx context.Context, request *blockchain.AddressRequest) (*blockchain.UnspentList, error) {
+	if len(request.Address) != crypto.AddressLen {
+		return nil, status.Errorf(codes.InvalidArgument, "address should be %d bytes", crypto.AddressLen)
+	}
+
+	utxos, err := server.chain.ListUnspent(request.Address)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	list := &blockchain.UnspentList{
+		Outputs: make([]*blockchain.Unspent, len(utxos)),
+	}
+	for i, utxo := range utxos {
+		list.Outputs[i] = &blockchain.Unspent{
+			OutPoint: utxo.OutPoint.Proto(),
+			Amount:   utxo.Amount,
+			Address:  request.Address,
+			Staked:   utxo.Staked,
+		}
+	}
+
+	return list, nil
+}
+
+func (server *Server) GetSupply(ctx context.Context, request *blockchain.GetSupplyRequest) (*blockchain.Supply, error) {
+	supply, err := server.chain.Supply()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &blockchain.Supply{
+		Height:      int32(supply.Height),
+		Circulating: supply.Circulating,
+		Scheduled:   supply.Scheduled,
+		MaxSupply:   server.chain.MonetaryPolicy().MaxSupply,
+	}, nil
+}
+
+func (server *Server) GetMempoolInfo(ctx context.Context, request *blockchain.GetMempoolInfoRequest) (*blockchain.MempoolInfo, error) {
+	info := server.mempool.Info()
+
+	response := &blockchain.MempoolInfo{
+		Count:    int32(info.Count),
+		Bytes:    int64(info.Bytes),
+		MaxBytes: int64(info.MaxBytes),
+		Removed:  make(map[string]uint64),
+	}
+	if info.MinSize > 0 {
+		response.MinFeeRate = float64(info.MinFee) / float64(info.MinSize)
+	}
+	for reason, count := range info.Removed {
+		response.Removed[reason.String()] = count
+	}
+
+	return response, nil
+}
+
+func (server *Server) GetMempoolEntry(ctx context.Context, request *blockchain.GetMempoolEntryRequest) (*blockchain.MempoolEntry, error) {
+	entry, ok := server.mempool.Entry(hex.EncodeToString(request.Hash))
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "transaction %s not in the mempool", hex.EncodeToString(request.Hash))
+	}
+
+	response := &blockchain.MempoolEntry{
+		Transaction:  entry.Transaction,
+		Fee:          entry.Fee,
+		Size:         int64(entry.Size),
+		Added:        entry.Added.UnixNano(),
+		AncestorFee:  entry.AncestorFee,
+		AncestorSize: int64(entry.AncestorSize),
+	}
+	for _, hash := range entry.Parents {
+		b, _ := hex.DecodeString(hash)
+		response.Parents = append(response.Parents, b)
+	}
+	for _, hash := range entry.Children {
+		b, _ := hex.DecodeString(hash)
+		response.Children = append(response.Children, b)
+	}
+
+	return response, nil
+}
+
+func (server *Server) SubscribeMempool(request *blockchain.SubscribeMempoolRequest, stream blockchain.BlockChain_SubscribeMempoolServer) error {
+	events, cancel := server.mempool.Subscribe(mempoolEventBuffer)
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-server.quit:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "subscriber fell behind the mempool")
+			}
+
+			hash, _ := hex.DecodeString(event.Hash)
+			message := &blockchain.MempoolEvent{
+				Type:        blockchain.MempoolEvent_ADDED,
+				Hash:        hash,
+				Transaction: event.Transaction,
+				Fee:         event.Fee,
+				Size:        int64(event.Size),
+			}
+			if event.Type == MempoolRemoved {
+				message.Type = blockchain.MempoolEvent_REMOVED
+				message.Reason = event.Reason.String()
+			}
+
+			if err := stream.Send(message); err != nil {
+				return err
+			}
+		}
+	}
+}
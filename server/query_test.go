@@ -0,0 +1,299 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/blockchain/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestQueryBlocks(t *testing.T) {
+	var (
+		server = newTestValidator(t, 3)
+		ctx    = context.Background()
+	)
+
+	block, err := server.GetBlockByHeight(ctx, &blockchain.GetBlockByHeightRequest{Height: 2})
+	require.Nil(t, err)
+	require.Equal(t, int32(2), block.Header.Height)
+
+	fetched, err := server.GetBlockByHash(ctx, &blockchain.GetBlockByHashRequest{Hash: types.HashBlock(block)})
+	require.Nil(t, err)
+	require.Equal(t, types.HashBlock(block), types.HashBlock(fetched))
+
+	_, err = server.GetBlockByHeight(ctx, &blockchain.GetBlockByHeightRequest{Height: 4})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = server.GetBlockByHeight(ctx, &blockchain.GetBlockByHeightRequest{Height: -1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.GetBlockByHash(ctx, &blockchain.GetBlockByHashRequest{Hash: util.RandomHash()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestQueryTransaction(t *testing.T) {
+	var (
+		server = NewServer(ServerConfig{PrivateKey: crypto.GeneratePrivateKey()}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		tx     = genesisSpend(t, server.chain, 100)
+		ctx    = context.Background()
+	)
+
+	_, err := server.GetTransaction(ctx, &blockchain.GetTransactionRequest{Hash: types.HashTransaction(tx)})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	require.Nil(t, server.mempool.Add(tx))
+	_, err = server.createBlock()
+	require.Nil(t, err)
+
+	info, err := server.GetTransaction(ctx, &blockchain.GetTransactionRequest{Hash: types.HashTransaction(tx)})
+	require.Nil(t, err)
+	require.Equal(t, types.HashTransaction(tx), types.HashTransaction(info.Transaction))
+	require.Equal(t, int64(900), info.Fee)
+}
+
+func TestQueryChainInfo(t *testing.T) {
+	var (
+		server = newTestValidator(t, 2)
+		ctx    = context.Background()
+	)
+
+	info, err := server.GetChainInfo(ctx, &blockchain.GetChainInfoRequest{})
+	require.Nil(t, err)
+
+	tip, err := server.chain.GetBlockByHeight(2)
+	require.Nil(t, err)
+
+	require.Equal(t, int32(2), info.Height)
+	require.Equal(t, types.HashBlock(tip), info.TipHash)
+	require.Equal(t, types.HashBlock(createGenesisBlock(server.chain.genesis, server.chain.policy)), info.GenesisHash)
+	require.Equal(t, server.PrivateKey.Public().Bytes(), info.ValidatorKey)
+}
+
+func TestQueryBalance(t *testing.T) {
+	var (
+		server         = NewServer(ServerConfig{PrivateKey: crypto.GeneratePrivateKey()}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		genesisAddress = crypto.NewPrivateKeyFromString(seed).Public().Address().Bytes()
+		tx             = genesisSpend(t, server.chain, 100)
+		recipient      = tx.Outputs[0].Address
+		ctx            = context.Background()
+	)
+
+	balance, err := server.GetBalance(ctx, &blockchain.AddressRequest{Address: genesisAddress})
+	require.Nil(t, err)
+	require.Equal(t, int64(1000), balance.Amount)
+
+	require.Nil(t, server.mempool.Add(tx))
+	_, err = server.createBlock()
+	require.Nil(t, err)
+
+	balance, err = server.GetBalance(ctx, &blockchain.AddressRequest{Address: genesisAddress})
+	require.Nil(t, err)
+	require.Equal(t, int64(0), balance.Amount)
+
+	balance, err = server.GetBalance(ctx, &blockchain.AddressRequest{Address: recipient})
+	require.Nil(t, err)
+	require.Equal(t, int64(100), balance.Amount)
+
+	unspent, err := server.ListUnspent(ctx, &blockchain.AddressRequest{Address: recipient})
+	require.Nil(t, err)
+	require.Len(t, unspent.Outputs, 1)
+	require.Equal(t, types.HashTransaction(tx), unspent.Outputs[0].OutPoint.TxHash)
+	require.Equal(t, uint32(0), unspent.Outputs[0].OutPoint.Index)
+
+	_, err = server.GetBalance(ctx, &blockchain.AddressRequest{Address: util.RandomHash()})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestQuerySupply(t *testing.T) {
+	server := newTestValidator(t, 3)
+
+	supply, err := server.GetSupply(context.Background(), &blockchain.GetSupplyRequest{})
+	require.Nil(t, err)
+	require.Equal(t, int32(3), supply.Height)
+	require.Equal(t, int64(genesisAmount+3*DefaultMonetaryPolicy.InitialSubsidy), supply.Circulating)
+	require.Equal(t, supply.Circulating, supply.Scheduled)
+	require.Equal(t, DefaultMonetaryPolicy.MaxSupply, supply.MaxSupply)
+}
+
+func TestQueryMempool(t *testing.T) {
+	var (
+		server     = NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		genesisKey = crypto.NewPrivateKeyFromString(seed)
+		ctx        = context.Background()
+	)
+
+	genesis, err := server.chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	parent := signedTx([]spend{{genesisKey, outPoint(genesis.Transactions[0], 0)}}, payTo(genesisKey, 990))
+	child := signedTx([]spend{{genesisKey, outPoint(parent, 0)}}, payTo(crypto.GeneratePrivateKey(), 900))
+	_, err = server.SubmitPackage(ctx, &blockchain.Package{Transactions: []*blockchain.Transaction{parent, child}})
+	require.Nil(t, err)
+
+	info, err := server.GetMempoolInfo(ctx, &blockchain.GetMempoolInfoRequest{})
+	require.Nil(t, err)
+	require.Equal(t, int32(2), info.Count)
+	require.Equal(t, int64(proto.Size(parent)+proto.Size(child)), info.Bytes)
+	require.Equal(t, float64(10)/float64(proto.Size(parent)), info.MinFeeRate)
+	require.Equal(t, uint64(0), info.Removed["evicted"])
+
+	entry, err := server.GetMempoolEntry(ctx, &blockchain.GetMempoolEntryRequest{Hash: types.HashTransaction(child)})
+	require.Nil(t, err)
+	require.Equal(t, int64(90), entry.Fee)
+	require.Equal(t, [][]byte{types.HashTransaction(parent)}, entry.Parents)
+	require.Empty(t, entry.Children)
+	require.Equal(t, int64(100), entry.AncestorFee)
+	require.Equal(t, int64(proto.Size(parent)+proto.Size(child)), entry.AncestorSize)
+
+	_, err = server.GetMempoolEntry(ctx, &blockchain.GetMempoolEntryRequest{Hash: util.RandomHash()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestQueryBalanceSeparatesStake(t *testing.T) {
+	var (
+		fixture = newConsensusFixture(t, WithGenesis(Genesis{Consensus: ConsensusStake}))
+		server  = NewServer(ServerConfig{}, fixture.chain)
+		stake   = signedKindTx(blockchain.Transaction_STAKE, []spend{{fixture.alice, outPoint(fixture.split, 0)}}, payTo(fixture.alice, 200))
+		alice   = &blockchain.AddressRequest{Address: fixture.alice.Public().Address().Bytes()}
+		bob     = &blockchain.AddressRequest{Address: fixture.bob.Public().Address().Bytes()}
+		ctx     = context.Background()
+	)
+	require.Nil(t, mineBlock(fixture.chain, stake))
+
+	balance, err := server.GetBalance(ctx, alice)
+	require.Nil(t, err)
+	require.Equal(t, int64(0), balance.Amount)
+	require.Equal(t, int64(200), balance.Staked)
+
+	balance, err = server.GetBalance(ctx, bob)
+	require.Nil(t, err)
+	require.Equal(t, int64(700), balance.Amount)
+	require.Equal(t, int64(0), balance.Staked)
+
+	unspent, err := server.ListUnspent(ctx, alice)
+	require.Nil(t, err)
+	require.Len(t, unspent.Outputs, 1)
+	require.True(t, unspent.Outputs[0].Staked)
+
+	unspent, err = server.ListUnspent(ctx, bob)
+	require.Nil(t, err)
+	require.Len(t, unspent.Outputs, 1)
+	require.False(t, unspent.Outputs[0].Staked)
+}
+
+func TestSubscribeMempool(t *testing.T) {
+	var (
+		server  = NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		tx      = genesisSpend(t, server.chain, 100)
+		address = freeAddress(t)
+	)
+
+	go server.Start(address, nil)
+	defer server.Stop()
+
+	client, err := makeBlockChainClient(address)
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.SubscribeMempool(ctx, &blockchain.SubscribeMempoolRequest{})
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		server.mempool.lock.RLock()
+		defer server.mempool.lock.RUnlock()
+		return len(server.mempool.subscribers) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Nil(t, server.mempool.Add(tx))
+	server.mempool.Remove(tx, RemovedExpired)
+
+	event, err := stream.Recv()
+	require.Nil(t, err)
+	require.Equal(t, blockchain.MempoolEvent_ADDED, event.Type)
+	require.Equal(t, types.HashTransaction(tx), event.Hash)
+	require.Equal(t, int64(900), event.Fee)
+
+	event, err = stream.Recv()
+	require.Nil(t, err)
+	require.Equal(t, blockchain.MempoolEvent_REMOVED, event.Type)
+	require.Equal(t, "expired", event.Reason)
+}
+
+func TestStopWithSubscriber(t *testing.T) {
+	var (
+		server  = NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		address = freeAddress(t)
+	)
+
+	go server.Start(address, nil)
+
+	client, err := makeBlockChainClient(address)
+	require.Nil(t, err)
+
+	stream, err := client.SubscribeMempool(context.Background(), &blockchain.SubscribeMempoolRequest{})
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		server.mempool.lock.RLock()
+		defer server.mempool.lock.RUnlock()
+		return len(server.mempool.subscribers) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	stopped := make(chan error)
+	go func() { stopped <- server.Stop() }()
+
+	select {
+	case err := <-stopped:
+		require.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscriber kept the server from stopping")
+	}
+
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestQueryCommit(t *testing.T) {
+	var (
+		key    = crypto.GeneratePrivateKey()
+		chain  = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithGenesis(Genesis{Validators: []*crypto.PublicKey{key.Public()}}))
+		server = NewServer(ServerConfig{PrivateKey: key}, chain)
+		ctx    = context.Background()
+	)
+
+	block, err := server.createBlock()
+	require.Nil(t, err)
+	server.finality.Wake()
+
+	byHeight, err := server.GetCommit(ctx, &blockchain.GetCommitRequest{Height: 1})
+	require.Nil(t, err)
+	byHash, err := server.GetCommit(ctx, &blockchain.GetCommitRequest{Hash: types.HashBlock(block)})
+	require.Nil(t, err)
+	require.True(t, proto.Equal(byHeight, byHash))
+	require.Equal(t, types.HashBlock(block), byHeight.BlockHash)
+
+	info, err := server.GetChainInfo(ctx, &blockchain.GetChainInfoRequest{})
+	require.Nil(t, err)
+	require.Equal(t, int32(1), info.FinalizedHeight)
+	require.Equal(t, types.HashBlock(block), info.FinalizedHash)
+
+	_, err = server.createBlock()
+	require.Nil(t, err)
+	_, err = server.GetCommit(ctx, &blockchain.GetCommitRequest{Height: 3})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	vote := &blockchain.Vote{Type: blockchain.Vote_PREVOTE, Height: 2, BlockHash: util.RandomHash()}
+	types.SignVote(crypto.GeneratePrivateKey(), vote)
+	_, err = server.HandleVote(ctx, vote)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
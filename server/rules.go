@@ -0,0 +1,118 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	blockchain "github.com/blockchain/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	maxBlockSize         = 1 << 20
+	maxBlockTransactions = 1 << 14
+	// medianTimeBlocks is how many ancestors the timestamp of a block has
+	// to be later than the median of.
+	medianTimeBlocks = 11
+	// maxFutureBlockTime is how far ahead of the local clock a block may be
+	// timestamped.
+	maxFutureBlockTime = 2 * time.Hour
+)
+
+// supportedVersions are the header versions blocks may carry.
+var supportedVersions = map[int32]bool{1: true}
+
+// RuleCode identifies the header rule a block breaks.
+type RuleCode int
+
+const (
+	RuleHeight RuleCode = iota + 1
+	RuleVersion
+	RuleTimeTooOld
+	RuleTimeTooNew
+	RuleBlockSize
+	RuleTransactionCount
+)
+
+func (code RuleCode) String() string {
+	switch code {
+	case RuleHeight:
+		return "bad-height"
+	case RuleVersion:
+		return "bad-version"
+	case RuleTimeTooOld:
+		return "time-too-old"
+	case RuleTimeTooNew:
+		return "time-too-new"
+	case RuleBlockSize:
+		return "block-too-large"
+	case RuleTransactionCount:
+		return "too-many-transactions"
+	}
+
+	return fmt.Sprintf("rule-%d", int(code))
+}
+
+// RuleError is returned for a block breaking a header rule, Code tells which.
+type RuleError struct {
+	Code   RuleCode
+	Reason string
+}
+
+func (err *RuleError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Code, err.Reason)
+}
+
+func ruleError(code RuleCode, format string, args ...any) error {
+	return &RuleError{Code: code, Reason: fmt.Sprintf(format, args...)}
+}
+
+// validateHeader checks the header rules of a block extending parent, which
+// do not depend on the state of the main chain.
+func validateHeader(block *blockchain.Block, parent *BlockNode, now time.Time) error {
+	header := block.Header
+
+	if int(header.Height) != parent.Height+1 {
+		return ruleError(RuleHeight, "block height %d does not follow parent height %d", header.Height, parent.Height)
+	}
+
+	if !supportedVersions[header.Version] {
+		return ruleError(RuleVersion, "unsupported block version %d", header.Version)
+	}
+
+	if median := medianTime(parent); header.Timestamp <= median {
+		return ruleError(RuleTimeTooOld, "block timestamp %d is not after the median time %d of the previous blocks", header.Timestamp, median)
+	}
+
+	if limit := now.Add(maxFutureBlockTime).UnixNano(); header.Timestamp > limit {
+		return ruleError(RuleTimeTooNew, "block timestamp %d is more than %s ahead", header.Timestamp, maxFutureBlockTime)
+	}
+
+	return validateBlockSize(block)
+}
+
+// validateBlockSize checks the limits on the transactions a block carries.
+func validateBlockSize(block *blockchain.Block) error {
+	if len(block.Transactions) > maxBlockTransactions {
+		return ruleError(RuleTransactionCount, "block has %d transactions, at most %d are allowed", len(block.Transactions), maxBlockTransactions)
+	}
+
+	if size := proto.Size(block); size > maxBlockSize {
+		return ruleError(RuleBlockSize, "block is %d bytes, at most %d are allowed", size, maxBlockSize)
+	}
+
+	return nil
+}
+
+// medianTime returns the median timestamp of the node and up to
+// medianTimeBlocks-1 of its ancestors.
+func medianTime(node *BlockNode) int64 {
+	timestamps := make([]int64, 0, medianTimeBlocks)
+	for ; node != nil && len(timestamps) < medianTimeBlocks; node = node.Parent {
+		timestamps = append(timestamps, node.Header.Timestamp)
+	}
+
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	return timestamps[len(timestamps)/2]
+}
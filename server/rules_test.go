@@ -0,0 +1,68 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderRules(t *testing.T) {
+	var (
+		chain = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		key   = crypto.GeneratePrivateKey()
+	)
+
+	tooMany := make([]*blockchain.Transaction, maxBlockTransactions+1)
+	for i := range tooMany {
+		tooMany[i] = &blockchain.Transaction{Version: 1}
+	}
+
+	cases := map[string]struct {
+		code   RuleCode
+		modify func(block *blockchain.Block)
+	}{
+		"height skips ahead": {RuleHeight, func(block *blockchain.Block) { block.Header.Height = 5 }},
+		"height repeats":     {RuleHeight, func(block *blockchain.Block) { block.Header.Height = 0 }},
+		"unknown version":    {RuleVersion, func(block *blockchain.Block) { block.Header.Version = 2 }},
+		"time before median": {RuleTimeTooOld, func(block *blockchain.Block) { block.Header.Timestamp = 20 }},
+		"time in the future": {RuleTimeTooNew, func(block *blockchain.Block) {
+			block.Header.Timestamp = time.Now().Add(maxFutureBlockTime + time.Minute).UnixNano()
+		}},
+		"too many transactions": {RuleTransactionCount, func(block *blockchain.Block) { block.Transactions = tooMany }},
+		"too large": {RuleBlockSize, func(block *blockchain.Block) {
+			block.Transactions = []*blockchain.Transaction{{Outputs: []*blockchain.TxOutput{{Address: make([]byte, maxBlockSize)}}}}
+		}},
+	}
+
+	// the median of the timestamps 0, 10, 20 and 30 is 20
+	for _, timestamp := range []int64{10, 30, 20} {
+		block, err := chain.NewBlock(nil, nil)
+		require.Nil(t, err)
+		block.Header.Timestamp = timestamp
+		types.SignBlock(key, block)
+		require.Nil(t, chain.AddBlock(block))
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			block, err := chain.NewBlock(nil, nil)
+			require.Nil(t, err)
+			c.modify(block)
+			types.SignBlock(key, block)
+
+			var ruleErr *RuleError
+			require.ErrorAs(t, chain.AddBlock(block), &ruleErr)
+			require.Equal(t, c.code, ruleErr.Code)
+		})
+	}
+
+	block, err := chain.NewBlock(nil, nil)
+	require.Nil(t, err)
+	block.Header.Timestamp = 21
+	types.SignBlock(key, block)
+	require.Nil(t, chain.AddBlock(block))
+}
@@ -0,0 +1,475 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	segmentExt            = ".seg"
+	recordHeaderLen       = 8
+	defaultMaxSegmentSize = 64 << 20
+	defaultSyncInterval   = time.Second
+)
+
+const (
+	opPut byte = iota + 1
+	opDelete
+)
+
+var errNotFound = errors.New("not found")
+
+type SyncPolicy int
+
+const (
+	// SyncAlways fsyncs the active segment after every write.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval fsyncs the active segment in the background every SyncInterval.
+	SyncInterval
+	// SyncNever leaves flushing to the operating system until the store is closed.
+	//
+	// Under either relaxed policy the chain still syncs its stores around every
+	// block it connects or disconnects, only the writes in between are left
+	// to the policy.
+	SyncNever
+)
+
+type DiskStoreConfig struct {
+	Dir            string
+	MaxSegmentSize int64
+	Sync           SyncPolicy
+	SyncInterval   time.Duration
+}
+
+type recordPosition struct {
+	segment int
+	offset  int64
+	size    int
+}
+
+type segment struct {
+	id   int
+	file *os.File
+	size int64
+}
+
+// segmentLog is an append-only key/value log split over numbered segment
+// files. Every record is checksummed; the index of live keys is kept in memory
+// and rebuilt by replaying the segments on open.
+type segmentLog struct {
+	lock     sync.RWMutex
+	config   DiskStoreConfig
+	segments map[int]*segment
+	active   *segment
+	index    map[string]recordPosition
+	dirty    bool
+	closed   bool
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func openSegmentLog(config DiskStoreConfig) (*segmentLog, error) {
+	if config.MaxSegmentSize <= 0 {
+		config.MaxSegmentSize = defaultMaxSegmentSize
+	}
+	if config.SyncInterval <= 0 {
+		config.SyncInterval = defaultSyncInterval
+	}
+
+	if err := os.MkdirAll(config.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	segLog := &segmentLog{
+		config:   config,
+		segments: make(map[int]*segment),
+		index:    make(map[string]recordPosition),
+		done:     make(chan struct{}),
+	}
+
+	ids, err := segmentIDs(config.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range ids {
+		last := i == len(ids)-1
+		if err := segLog.loadSegment(id, last); err != nil {
+			segLog.closeFiles()
+			return nil, err
+		}
+	}
+
+	if segLog.active == nil {
+		if err := segLog.rollSegment(); err != nil {
+			segLog.closeFiles()
+			return nil, err
+		}
+	}
+
+	if config.Sync == SyncInterval {
+		segLog.wg.Add(1)
+		go segLog.syncLoop()
+	}
+
+	return segLog, nil
+}
+
+func segmentIDs(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []int{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimSuffix(name, segmentExt))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+func (segLog *segmentLog) segmentPath(id int) string {
+	return filepath.Join(segLog.config.Dir, fmt.Sprintf("%06d%s", id, segmentExt))
+}
+
+// loadSegment replays a segment into the index. A torn or corrupt record at
+// the tail of the last segment is the result of a crash during a write, so the
+// segment is truncated back to the last complete record. Corruption anywhere
+// else is reported as an error.
+func (segLog *segmentLog) loadSegment(id int, last bool) error {
+	file, err := os.OpenFile(segLog.segmentPath(id), os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+
+	seg := &segment{id: id, file: file}
+	segLog.segments[id] = seg
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(file)
+	var offset int64
+	for {
+		op, key, _, size, err := readRecord(reader, info.Size()-offset-recordHeaderLen)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if !last {
+				return fmt.Errorf("corrupt record in segment %s at offset %d: %w", segLog.segmentPath(id), offset, err)
+			}
+			if err := file.Truncate(offset); err != nil {
+				return err
+			}
+			if err := file.Sync(); err != nil {
+				return err
+			}
+			break
+		}
+
+		switch op {
+		case opPut:
+			segLog.index[key] = recordPosition{segment: id, offset: offset, size: size}
+		case opDelete:
+			delete(segLog.index, key)
+		}
+		offset += int64(size)
+	}
+
+	seg.size = offset
+	if last {
+		segLog.active = seg
+	}
+
+	return nil
+}
+
+func (segLog *segmentLog) rollSegment() error {
+	id := 1
+	if segLog.active != nil {
+		if err := segLog.active.file.Sync(); err != nil {
+			return err
+		}
+		id = segLog.active.id + 1
+	}
+
+	file, err := os.OpenFile(segLog.segmentPath(id), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	seg := &segment{id: id, file: file}
+	segLog.segments[id] = seg
+	segLog.active = seg
+	segLog.dirty = false
+
+	return syncDir(segLog.config.Dir)
+}
+
+func (segLog *segmentLog) Put(key string, value []byte) error {
+	return segLog.write(opPut, key, value)
+}
+
+func (segLog *segmentLog) Delete(key string) error {
+	return segLog.write(opDelete, key, nil)
+}
+
+func (segLog *segmentLog) write(op byte, key string, value []byte) error {
+	segLog.lock.Lock()
+	defer segLog.lock.Unlock()
+
+	if segLog.closed {
+		return fmt.Errorf("store %s is closed", segLog.config.Dir)
+	}
+
+	record := encodeRecord(op, key, value)
+
+	if segLog.active.size > 0 && segLog.active.size+int64(len(record)) > segLog.config.MaxSegmentSize {
+		if err := segLog.rollSegment(); err != nil {
+			return err
+		}
+	}
+
+	offset := segLog.active.size
+	if _, err := segLog.active.file.WriteAt(record, offset); err != nil {
+		return err
+	}
+	segLog.active.size += int64(len(record))
+	segLog.dirty = true
+
+	if segLog.config.Sync == SyncAlways {
+		if err := segLog.active.file.Sync(); err != nil {
+			return err
+		}
+		segLog.dirty = false
+	}
+
+	if op == opDelete {
+		delete(segLog.index, key)
+	} else {
+		segLog.index[key] = recordPosition{segment: segLog.active.id, offset: offset, size: len(record)}
+	}
+
+	return nil
+}
+
+func (segLog *segmentLog) Get(key string) ([]byte, error) {
+	segLog.lock.RLock()
+	defer segLog.lock.RUnlock()
+
+	position, ok := segLog.index[key]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	return segLog.read(position)
+}
+
+func (segLog *segmentLog) Has(key string) bool {
+	segLog.lock.RLock()
+	defer segLog.lock.RUnlock()
+
+	_, ok := segLog.index[key]
+	return ok
+}
+
+// ForEach calls fn with every live key and value in the log. The log is
+// read-locked for the duration, so fn must not write to it.
+func (segLog *segmentLog) ForEach(fn func(key string, value []byte) error) error {
+	segLog.lock.RLock()
+	defer segLog.lock.RUnlock()
+
+	for key, position := range segLog.index {
+		value, err := segLog.read(position)
+		if err != nil {
+			return err
+		}
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (segLog *segmentLog) read(position recordPosition) ([]byte, error) {
+	seg, ok := segLog.segments[position.segment]
+	if !ok {
+		return nil, fmt.Errorf("missing segment %d", position.segment)
+	}
+
+	buf := make([]byte, position.size)
+	if _, err := seg.file.ReadAt(buf, position.offset); err != nil {
+		return nil, err
+	}
+
+	_, _, value, _, err := readRecord(bytes.NewReader(buf), int64(len(buf)-recordHeaderLen))
+	if err != nil {
+		return nil, fmt.Errorf("corrupt record in segment %d at offset %d: %w", position.segment, position.offset, err)
+	}
+
+	return value, nil
+}
+
+func (segLog *segmentLog) Sync() error {
+	segLog.lock.Lock()
+	defer segLog.lock.Unlock()
+
+	return segLog.sync()
+}
+
+func (segLog *segmentLog) sync() error {
+	if !segLog.dirty || segLog.closed {
+		return nil
+	}
+	if err := segLog.active.file.Sync(); err != nil {
+		return err
+	}
+	segLog.dirty = false
+
+	return nil
+}
+
+func (segLog *segmentLog) syncLoop() {
+	defer segLog.wg.Done()
+
+	ticker := time.NewTicker(segLog.config.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			segLog.Sync()
+		case <-segLog.done:
+			return
+		}
+	}
+}
+
+func (segLog *segmentLog) Close() error {
+	segLog.lock.Lock()
+	if segLog.closed {
+		segLog.lock.Unlock()
+		return nil
+	}
+	err := segLog.sync()
+	segLog.closed = true
+	close(segLog.done)
+	segLog.lock.Unlock()
+
+	segLog.wg.Wait()
+
+	if closeErr := segLog.closeFiles(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func (segLog *segmentLog) closeFiles() error {
+	var err error
+	for _, seg := range segLog.segments {
+		if closeErr := seg.file.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// encodeRecord lays a record out as
+// crc32(payload) | len(payload) | op | uvarint(len(key)) | key | value.
+func encodeRecord(op byte, key string, value []byte) []byte {
+	payload := make([]byte, 0, 1+binary.MaxVarintLen64+len(key)+len(value))
+	payload = append(payload, op)
+	payload = binary.AppendUvarint(payload, uint64(len(key)))
+	payload = append(payload, key...)
+	payload = append(payload, value...)
+
+	record := make([]byte, recordHeaderLen, recordHeaderLen+len(payload))
+	binary.LittleEndian.PutUint32(record[0:4], crc32.ChecksumIEEE(payload))
+	binary.LittleEndian.PutUint32(record[4:8], uint32(len(payload)))
+
+	return append(record, payload...)
+}
+
+// readRecord reads the next record, whose payload can not be longer than the
+// limit bytes left after its header. A longer length can only be a torn or
+// corrupt header, it is not trusted to allocate the payload.
+func readRecord(reader io.Reader, limit int64) (op byte, key string, value []byte, size int, err error) {
+	header := make([]byte, recordHeaderLen)
+	n, err := io.ReadFull(reader, header)
+	if err == io.EOF {
+		return 0, "", nil, 0, io.EOF
+	}
+	if err != nil {
+		return 0, "", nil, 0, fmt.Errorf("torn record header (%d bytes)", n)
+	}
+
+	checksum := binary.LittleEndian.Uint32(header[0:4])
+	length := binary.LittleEndian.Uint32(header[4:8])
+	if int64(length) > limit {
+		return 0, "", nil, 0, fmt.Errorf("record length %d exceeds the %d bytes left", length, limit)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return 0, "", nil, 0, fmt.Errorf("torn record payload")
+	}
+
+	if crc32.ChecksumIEEE(payload) != checksum {
+		return 0, "", nil, 0, fmt.Errorf("checksum mismatch")
+	}
+
+	if len(payload) < 2 {
+		return 0, "", nil, 0, fmt.Errorf("record too short")
+	}
+
+	op = payload[0]
+	keyLen, n := binary.Uvarint(payload[1:])
+	if n <= 0 || uint64(len(payload)-1-n) < keyLen {
+		return 0, "", nil, 0, fmt.Errorf("invalid key length")
+	}
+
+	start := 1 + n
+	key = string(payload[start : start+int(keyLen)])
+	value = payload[start+int(keyLen):]
+
+	return op, key, value, recordHeaderLen + len(payload), nil
+}
+
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return file.Sync()
+}
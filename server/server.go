@@ -3,6 +3,8 @@ package server
 import (
 	"context"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"log"
 	"net"
 	"sync"
@@ -12,65 +14,75 @@ import (
 	blockchain "github.com/blockchain/proto"
 	"github.com/blockchain/types"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
-const blockTime = time.Second * 5
+const (
+	blockTime       = time.Second * 5
+	knownBlocksSize = 1024
+	knownVotesSize  = 4096
+	// minerRetryDelay is how long the miner waits after failing to create a
+	// block before it tries again.
+	minerRetryDelay = time.Second
+	// blockOverhead is the room kept for the header and the coinbase when
+	// filling a block with pending transactions.
+	blockOverhead = 1024
+	// listenAddressKey is the metadata key under which broadcasts carry the
+	// listen address of the sender.
+	listenAddressKey = "listen-address"
+	// mempoolEventBuffer is how many events a SubscribeMempool stream may
+	// fall behind before it is closed.
+	mempoolEventBuffer = 1024
+)
 
-type Mempool struct {
-	lock         sync.RWMutex
-	transactions map[string]*blockchain.Transaction
-}
+var errMiningInterrupted = errors.New("mining interrupted by a new tip")
 
-func NewMempool() *Mempool {
-	return &Mempool{
-		transactions: make(map[string]*blockchain.Transaction),
-	}
+// hashSet remembers up to size hashes, forgetting the oldest ones first.
+type hashSet struct {
+	lock   sync.Mutex
+	size   int
+	hashes map[string]struct{}
+	order  []string
 }
 
-func (pool *Mempool) Clear() []*blockchain.Transaction {
-	pool.lock.RLock()
-	defer pool.lock.RUnlock()
-
-	transactions := make([]*blockchain.Transaction, len(pool.transactions))
-	i := 0
-	for key, value := range pool.transactions {
-		delete(pool.transactions, key)
-		transactions[i] = value
-		i++
+func newHashSet(size int) *hashSet {
+	return &hashSet{
+		size:   size,
+		hashes: make(map[string]struct{}),
 	}
-
-	return transactions
 }
 
-func (pool *Mempool) Len() int {
-	pool.lock.RLock()
-	defer pool.lock.RUnlock()
+// Has reports whether the hash is remembered.
+func (set *hashSet) Has(hash string) bool {
+	set.lock.Lock()
+	defer set.lock.Unlock()
 
-	return len(pool.transactions)
-}
-
-func (pool *Mempool) Has(transaction *blockchain.Transaction) bool {
-	pool.lock.RLock()
-	defer pool.lock.RUnlock()
-
-	hash := hex.EncodeToString(types.HashTransaction(transaction))
-	_, ok := pool.transactions[hash]
+	_, ok := set.hashes[hash]
 	return ok
 }
 
-func (pool *Mempool) Add(transaction *blockchain.Transaction) bool {
-	if pool.Has(transaction) {
+// Add records the hash and reports whether it was not known before.
+func (set *hashSet) Add(hash string) bool {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+
+	if _, ok := set.hashes[hash]; ok {
 		return false
 	}
 
-	pool.lock.Lock()
-	defer pool.lock.Unlock()
+	if len(set.order) == set.size {
+		delete(set.hashes, set.order[0])
+		set.order = set.order[1:]
+	}
 
-	hash := hex.EncodeToString(types.HashTransaction(transaction))
-	pool.transactions[hash] = transaction
+	set.hashes[hash] = struct{}{}
+	set.order = append(set.order, hash)
 	return true
 }
 
@@ -78,6 +90,10 @@ type ServerConfig struct {
 	Version       string
 	ListenAddress string
 	PrivateKey    *crypto.PrivateKey
+	Mempool       MempoolConfig
+	// MempoolFile is where the pending transactions are dumped on Stop and
+	// loaded from on Start, empty to keep them in memory only.
+	MempoolFile string
 }
 
 type Server struct {
@@ -88,32 +104,73 @@ type Server struct {
 	peerLock sync.RWMutex
 	peers    map[blockchain.BlockChainClient]*blockchain.HandshakeMessage
 	mempool  *Mempool
+	chain    *Chain
+
+	knownBlocks *hashSet
+	orphans     *orphanPool
+	syncLock    sync.Mutex
+
+	// finality is nil when the chain has no validator set.
+	finality       *finality
+	knownVotes     *hashSet
+	commitSyncLock sync.Mutex
+
+	grpcServer *grpc.Server
+	quit       chan struct{}
+	stopOnce   sync.Once
+
+	// loops are the background loops Stop waits for, none is started once
+	// quit is closed.
+	loopLock sync.Mutex
+	loops    sync.WaitGroup
 
 	blockchain.UnimplementedBlockChainServer
 }
 
-func NewServer(config ServerConfig) *Server {
+func NewServer(config ServerConfig, chain *Chain) *Server {
 	logger, _ := zap.NewDevelopment()
+	mempool := NewMempool(chain, config.Mempool)
+	chain.Subscribe(mempool)
 
-	return &Server{
+	server := &Server{
 		peers:        make(map[blockchain.BlockChainClient]*blockchain.HandshakeMessage),
 		logger:       logger.Sugar(),
-		mempool:      NewMempool(),
+		mempool:      mempool,
+		chain:        chain,
+		knownBlocks:  newHashSet(knownBlocksSize),
+		orphans:      newOrphanPool(),
+		knownVotes:   newHashSet(knownVotesSize),
+		grpcServer:   grpc.NewServer(),
+		quit:         make(chan struct{}),
 		ServerConfig: config,
 	}
+	blockchain.RegisterBlockChainServer(server.grpcServer, server)
+
+	if chain.genesis.Consensus == ConsensusAuthority && len(chain.genesis.Validators) > 0 {
+		server.finality = newFinality(chain, config.PrivateKey)
+		chain.Subscribe(server.finality)
+	}
+
+	return server
 }
 
 func (server *Server) Start(listenAddress string, bootstrapServers []string) error {
 	server.ListenAddress = listenAddress
-	opts := []grpc.ServerOption{}
-	grpcServer := grpc.NewServer(opts...)
+
+	if server.MempoolFile != "" {
+		loaded, err := server.mempool.Load(server.MempoolFile)
+		if err != nil {
+			server.logger.Errorw("could not load mempool", "file", server.MempoolFile, "err", err)
+		} else {
+			server.logger.Infow("loaded mempool", "file", server.MempoolFile, "transactions", loaded)
+		}
+	}
 
 	ln, err := net.Listen("tcp", listenAddress)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	blockchain.RegisterBlockChainServer(grpcServer, server)
 	server.logger.Info("node running on port:", listenAddress)
 
 	if len(bootstrapServers) > 0 {
@@ -121,10 +178,58 @@ func (server *Server) Start(listenAddress string, bootstrapServers []string) err
 	}
 
 	if server.PrivateKey != nil {
-		go server.validatorLoop()
+		if server.chain.genesis.Consensus == ConsensusWork {
+			server.startLoop(server.minerLoop)
+		} else {
+			server.startLoop(server.validatorLoop)
+		}
+	}
+
+	if server.finality != nil {
+		server.startLoop(func() { server.finality.run(server.quit, server.broadcastVotes) })
+	}
+
+	return server.grpcServer.Serve(ln)
+}
+
+// startLoop runs the loop in the background unless the server is stopping.
+func (server *Server) startLoop(loop func()) {
+	server.loopLock.Lock()
+	defer server.loopLock.Unlock()
+
+	select {
+	case <-server.quit:
+		return
+	default:
 	}
 
-	return grpcServer.Serve(ln)
+	server.loops.Add(1)
+	go func() {
+		defer server.loops.Done()
+		loop()
+	}()
+}
+
+// Stop stops the validator and miner loops and waits for them to return,
+// waits for the pending requests to finish and dumps the mempool when a
+// MempoolFile is configured.
+func (server *Server) Stop() error {
+	var err error
+
+	server.stopOnce.Do(func() {
+		server.loopLock.Lock()
+		close(server.quit)
+		server.loopLock.Unlock()
+
+		server.grpcServer.GracefulStop()
+		server.loops.Wait()
+
+		if server.MempoolFile != "" {
+			err = server.mempool.Dump(server.MempoolFile)
+		}
+	})
+
+	return err
 }
 
 func (server *Server) Handshake(ctx context.Context, message *blockchain.HandshakeMessage) (*blockchain.HandshakeMessage, error) {
@@ -142,43 +247,326 @@ func (server *Server) HandleTransaction(ctx context.Context, tx *blockchain.Tran
 	peer, _ := peer.FromContext(ctx)
 	hash := hex.EncodeToString(types.HashTransaction(tx))
 
-	if server.mempool.Add(tx) {
-		server.logger.Debugw("received transaction", "from", peer.Addr, "hash", hash, "we", server.ListenAddress)
+	if err := server.mempool.Add(tx); err != nil {
+		if errors.Is(err, ErrTxAlreadyKnown) {
+			return &blockchain.Ack{}, nil
+		}
+		return nil, status.Errorf(rejectionCode(err), "rejected transaction %s: %s", hash, err)
+	}
 
-		go func() {
-			if err := server.broadcast(tx); err != nil {
-				server.logger.Errorw("broadcast error", err)
-			}
-		}()
+	server.logger.Debugw("received transaction", "from", peer.Addr, "hash", hash, "we", server.ListenAddress)
+
+	go func() {
+		if err := server.broadcast(tx); err != nil {
+			server.logger.Errorw("broadcast error", "err", err)
+		}
+	}()
+
+	return &blockchain.Ack{}, nil
+}
+
+// rejectionCode tells the client to retry a transaction turned away by a
+// full mempool, while any other rejection is for the transaction itself.
+func rejectionCode(err error) codes.Code {
+	if errors.Is(err, ErrMempoolFull) {
+		return codes.ResourceExhausted
+	}
+	return codes.InvalidArgument
+}
+
+func (server *Server) SubmitPackage(ctx context.Context, pkg *blockchain.Package) (*blockchain.Ack, error) {
+	if err := server.mempool.AddPackage(pkg.Transactions); err != nil {
+		if errors.Is(err, ErrTxAlreadyKnown) {
+			return &blockchain.Ack{}, nil
+		}
+		return nil, status.Errorf(rejectionCode(err), "rejected package: %s", err)
+	}
+
+	server.logger.Debugw("received package", "transactions", len(pkg.Transactions), "we", server.ListenAddress)
+
+	go func() {
+		if err := server.broadcast(pkg); err != nil {
+			server.logger.Errorw("broadcast error", "err", err)
+		}
+	}()
+
+	return &blockchain.Ack{}, nil
+}
+
+func (server *Server) HandleBlock(ctx context.Context, block *blockchain.Block) (*blockchain.Ack, error) {
+	if block.GetHeader() == nil {
+		return nil, status.Error(codes.InvalidArgument, "block has no header")
+	}
+
+	hash := hex.EncodeToString(types.HashBlock(block))
+
+	if server.knownBlocks.Has(hash) || server.chain.HasBlock(types.HashBlock(block)) {
+		return &blockchain.Ack{}, nil
+	}
+	server.updateSenderHeight(ctx, int(block.Header.Height))
+
+	// the parent may still be on its way, keep the block until it arrives
+	if !server.chain.HasBlock(block.Header.PreviousHash) && !server.chain.IsInvalid(block.Header.PreviousHash) {
+		if server.orphans.Add(block) {
+			server.logger.Debugw("received orphan block", "hash", hash, "height", block.Header.Height, "we", server.ListenAddress)
+			go server.syncWithPeers()
+		}
+		return &blockchain.Ack{}, nil
+	}
+
+	if err := server.chain.AddBlock(block); err != nil {
+		server.dropInvalidOrphans(types.HashBlock(block))
+		return nil, blockError(hash, err)
+	}
+
+	server.logger.Debugw("received block", "hash", hash, "height", block.Header.Height, "we", server.ListenAddress)
+	server.acceptBlock(block)
+
+	return &blockchain.Ack{}, nil
+}
+
+// blockError reports a broken header rule as a failed precondition, with the
+// code of the rule as the reason, and any other rejection as an invalid
+// argument.
+func blockError(hash string, err error) error {
+	var ruleErr *RuleError
+	if !errors.As(err, &ruleErr) {
+		return status.Errorf(codes.InvalidArgument, "rejected block %s: %s", hash, err)
+	}
+
+	st, detailErr := status.New(codes.FailedPrecondition, fmt.Sprintf("rejected block %s: %s", hash, err)).
+		WithDetails(&errdetails.ErrorInfo{Reason: ruleErr.Code.String()})
+	if detailErr != nil {
+		return status.Errorf(codes.FailedPrecondition, "rejected block %s: %s", hash, err)
+	}
+
+	return st.Err()
+}
+
+// acceptBlock relays a block added to the chain and connects the orphans
+// that were waiting for it.
+func (server *Server) acceptBlock(block *blockchain.Block) {
+	hash := hex.EncodeToString(types.HashBlock(block))
+	server.knownBlocks.Add(hash)
+
+	go func() {
+		if err := server.broadcast(block); err != nil {
+			server.logger.Errorw("broadcast error", "err", err)
+		}
+	}()
+
+	server.connectOrphans(hash)
+}
+
+func (server *Server) connectOrphans(parent string) {
+	for _, orphan := range server.orphans.Children(parent) {
+		if err := server.chain.AddBlock(orphan); err != nil {
+			server.logger.Errorw("rejected orphan block", "hash", hex.EncodeToString(types.HashBlock(orphan)), "err", err)
+			server.dropInvalidOrphans(types.HashBlock(orphan))
+			continue
+		}
+		server.acceptBlock(orphan)
+	}
+}
+
+// dropInvalidOrphans drops the orphans descending from the block when it was
+// found invalid, they are invalid as well.
+func (server *Server) dropInvalidOrphans(hash []byte) {
+	if !server.chain.IsInvalid(hash) {
+		return
 	}
 
+	for _, orphan := range server.orphans.Children(hex.EncodeToString(hash)) {
+		server.chain.Invalidate(types.HashBlock(orphan))
+		server.dropInvalidOrphans(types.HashBlock(orphan))
+	}
+}
+
+func (server *Server) HandleVote(ctx context.Context, vote *blockchain.Vote) (*blockchain.Ack, error) {
+	if server.finality == nil {
+		return nil, status.Error(codes.FailedPrecondition, "chain has no validator set")
+	}
+
+	if !server.knownVotes.Add(hex.EncodeToString(types.HashVote(vote))) {
+		return &blockchain.Ack{}, nil
+	}
+
+	if err := server.finality.AddVote(vote); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "rejected vote: %s", err)
+	}
+
+	if int(vote.Height) > server.finality.Height() {
+		go server.syncCommits()
+	}
+
+	go func() {
+		if err := server.broadcast(vote); err != nil {
+			server.logger.Errorw("broadcast error", "err", err)
+		}
+	}()
+	server.broadcastVotes(server.finality.Votes())
+
 	return &blockchain.Ack{}, nil
 }
 
+// broadcastVotes sends the votes cast by this validator to the peers.
+func (server *Server) broadcastVotes(votes []*blockchain.Vote) {
+	for _, vote := range votes {
+		server.knownVotes.Add(hex.EncodeToString(types.HashVote(vote)))
+
+		go func(vote *blockchain.Vote) {
+			if err := server.broadcast(vote); err != nil {
+				server.logger.Errorw("broadcast error", "err", err)
+			}
+		}(vote)
+	}
+}
+
 func (server *Server) validatorLoop() {
 	server.logger.Infow("stating validator loop", "publicKey", server.PrivateKey.Public(), "blockTime", blockTime)
 	ticker := time.NewTicker(blockTime)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-server.quit:
+			return
+		}
+
+		if !server.chain.CanPropose(server.PrivateKey.Public()) {
+			continue
+		}
+
+		server.produceBlock()
+	}
+}
+
+// minerLoop mines blocks back to back on proof-of-work networks, the search
+// restarts whenever the tip changes.
+func (server *Server) minerLoop() {
+	server.logger.Infow("starting miner loop", "publicKey", server.PrivateKey.Public())
 
 	for {
-		<-ticker.C
+		select {
+		case <-server.quit:
+			return
+		default:
+		}
+
+		// the same failure is likely to happen again right away
+		if err := server.produceBlock(); err != nil {
+			select {
+			case <-time.After(minerRetryDelay):
+			case <-server.quit:
+				return
+			}
+		}
+	}
+}
+
+// produceBlock creates a block on the tip and broadcasts it. It returns the
+// error that kept it from creating one, which has been logged already, but not
+// the interruption of the search by a new tip.
+func (server *Server) produceBlock() error {
+	block, err := server.createBlock()
+	if errors.Is(err, errMiningInterrupted) {
+		return nil
+	}
+	if err != nil {
+		server.logger.Errorw("could not create block", "err", err)
+		return err
+	}
+
+	hash := hex.EncodeToString(types.HashBlock(block))
+	server.knownBlocks.Add(hash)
+	server.logger.Debugw("created new block", "height", block.Header.Height, "hash", hash, "lenTx", len(block.Transactions))
 
-		transactions := server.mempool.Clear()
+	go func() {
+		if err := server.broadcast(block); err != nil {
+			server.logger.Errorw("broadcast error", "err", err)
+		}
+	}()
+
+	return nil
+}
+
+func (server *Server) createBlock() (*blockchain.Block, error) {
+	transactions := server.mempool.Select(maxBlockSize - blockOverhead)
+	accepted, rejected := server.chain.SelectTransactions(transactions)
+	// parents come first, so cutting the tail keeps the block valid
+	if len(accepted) >= maxBlockTransactions {
+		accepted = accepted[:maxBlockTransactions-1]
+	}
+
+	for _, tx := range rejected {
+		server.mempool.Remove(tx, RemovedInvalid)
+	}
 
-		server.logger.Debugw("time to create new block", "lenTx", len(transactions))
+	block, err := server.chain.NewBlock(server.PrivateKey.Public().Address().Bytes(), accepted)
+	if err != nil {
+		return nil, err
+	}
+
+	if server.chain.genesis.Consensus == ConsensusWork {
+		height := server.chain.Height()
+		stop := func() bool {
+			select {
+			case <-server.quit:
+				return true
+			default:
+				return server.chain.Height() != height
+			}
+		}
+		if !server.chain.Mine(block, stop) {
+			return nil, errMiningInterrupted
+		}
+	} else {
+		types.SignBlock(server.PrivateKey, block)
+	}
+
+	if err := server.chain.AddBlock(block); err != nil {
+		return nil, err
 	}
+
+	return block, nil
 }
 
 func (server *Server) broadcast(message any) error {
-	for peer := range server.peers {
+	var broadcastErr error
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), listenAddressKey, server.ListenAddress)
+	for _, peer := range server.getPeers() {
+		var err error
+
 		switch v := message.(type) {
 		case *blockchain.Transaction:
-			_, err := peer.HandleTransaction(context.Background(), v)
-			if err != nil {
-				return err
-			}
+			_, err = peer.HandleTransaction(ctx, v)
+		case *blockchain.Package:
+			_, err = peer.SubmitPackage(ctx, v)
+		case *blockchain.Block:
+			_, err = peer.HandleBlock(ctx, v)
+		case *blockchain.Vote:
+			_, err = peer.HandleVote(ctx, v)
+		}
+
+		if err != nil && broadcastErr == nil {
+			broadcastErr = err
 		}
 	}
-	return nil
+	return broadcastErr
+}
+
+func (server *Server) getPeers() []blockchain.BlockChainClient {
+	server.peerLock.RLock()
+	defer server.peerLock.RUnlock()
+
+	peers := make([]blockchain.BlockChainClient, 0, len(server.peers))
+	for peer := range server.peers {
+		peers = append(peers, peer)
+	}
+
+	return peers
 }
 
 func (server *Server) addPeer(client blockchain.BlockChainClient, message *blockchain.HandshakeMessage) {
@@ -191,6 +579,10 @@ func (server *Server) addPeer(client blockchain.BlockChainClient, message *block
 
 	server.peers[client] = message
 	server.logger.Debugf("[%s]: new peer connected (%s) - height (%d)", server.ListenAddress, message.ListenAddress, message.Height)
+
+	if int(message.Height) > server.chain.Height() {
+		go server.syncWithPeers()
+	}
 }
 
 func (server *Server) deletePeer(c blockchain.BlockChainClient) {
@@ -205,7 +597,7 @@ func (server *Server) bootstrapNetwork(addresses []string) error {
 		if !server.canConnectWith(address) {
 			continue
 		}
-    
+
 		server.logger.Debugw("dialing remote server", "from", server.ListenAddress, "to", address)
 		client, version, err := server.dialRemoteServer(address)
 		if err != nil {
@@ -235,7 +627,7 @@ func (server *Server) dialRemoteServer(listenAddress string) (blockchain.BlockCh
 func (server *Server) getVersion() *blockchain.HandshakeMessage {
 	return &blockchain.HandshakeMessage{
 		Version:       "blocker-0.1",
-		Height:        0,
+		Height:        int32(server.chain.Height()),
 		ListenAddress: server.ListenAddress,
 		PeerList:      server.getPeerList(),
 	}
@@ -0,0 +1,347 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/blockchain/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func genesisSpend(t *testing.T, chain *Chain, amount int64) *blockchain.Transaction {
+	privateKey := crypto.NewPrivateKeyFromString(seed)
+	genesis, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	tx := &blockchain.Transaction{
+		Version: 1,
+		Inputs: []*blockchain.TxInput{
+			{
+				PreviousTxHash:   types.HashTransaction(genesis.Transactions[0]),
+				PreviousOutIndex: 0,
+				PublicKey:        privateKey.Public().Bytes(),
+			},
+		},
+		Outputs: []*blockchain.TxOutput{
+			{
+				Amount:  amount,
+				Address: crypto.GeneratePrivateKey().Public().Address().Bytes(),
+			},
+		},
+	}
+	tx.Inputs[0].Signature = types.SignTransaction(privateKey, tx).Bytes()
+
+	return tx
+}
+
+func TestCreateBlock(t *testing.T) {
+	var (
+		chain  = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		server = NewServer(ServerConfig{PrivateKey: crypto.GeneratePrivateKey()}, chain)
+		valid  = genesisSpend(t, chain, 100)
+		double = genesisSpend(t, chain, 200)
+	)
+
+	require.Nil(t, server.mempool.Add(valid))
+	block, err := server.createBlock()
+	require.Nil(t, err)
+	require.Equal(t, 1, chain.Height())
+	require.Equal(t, int32(1), block.Header.Height)
+	require.Len(t, block.Transactions, 2)
+	require.True(t, types.IsCoinbase(block.Transactions[0]))
+	require.Equal(t, int64(DefaultMonetaryPolicy.InitialSubsidy+900), block.Transactions[0].Outputs[0].Amount)
+	require.Equal(t, server.PrivateKey.Public().Address().Bytes(), block.Transactions[0].Outputs[0].Address)
+	require.Equal(t, 0, server.mempool.Len())
+
+	// bypass admission, the transaction became invalid since it was accepted
+	server.mempool.insert(&mempoolEntry{tx: double, hash: hex.EncodeToString(types.HashTransaction(double))})
+	block, err = server.createBlock()
+	require.Nil(t, err)
+	require.Equal(t, 2, chain.Height())
+	require.Len(t, block.Transactions, 1)
+	require.Equal(t, int64(DefaultMonetaryPolicy.InitialSubsidy), block.Transactions[0].Outputs[0].Amount)
+	require.Equal(t, 0, server.mempool.Len())
+
+	tip, err := chain.GetBlockByHeight(1)
+	require.Nil(t, err)
+	require.Equal(t, hex.EncodeToString(types.HashBlock(tip)), hex.EncodeToString(block.Header.PreviousHash))
+}
+
+func TestHandleTransaction(t *testing.T) {
+	var (
+		server = NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		tx     = genesisSpend(t, server.chain, 100)
+		double = genesisSpend(t, server.chain, 200)
+		bogus  = &blockchain.Transaction{
+			Version: 1,
+			Inputs:  []*blockchain.TxInput{{PreviousTxHash: util.RandomHash()}},
+		}
+		ctx = peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{}})
+	)
+
+	_, err := server.HandleTransaction(ctx, tx)
+	require.Nil(t, err)
+	require.Equal(t, 1, server.mempool.Len())
+
+	_, err = server.HandleTransaction(ctx, tx)
+	require.Nil(t, err)
+
+	_, err = server.HandleTransaction(ctx, double)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Contains(t, err.Error(), "already spent by pending transaction")
+
+	_, err = server.HandleTransaction(ctx, bogus)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, 1, server.mempool.Len())
+}
+
+func TestHandleTransactionFullMempool(t *testing.T) {
+	var (
+		chain          = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+		key, outPoints = fanOut(t, chain, 3)
+		to             = crypto.GeneratePrivateKey()
+		high           = signedTx([]spend{{key, outPoints[1]}}, payTo(to, 90))
+		low            = signedTx([]spend{{key, outPoints[2]}}, payTo(to, 99))
+		server         = NewServer(ServerConfig{Mempool: MempoolConfig{MaxBytes: proto.Size(high)}}, chain)
+		ctx            = peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{}})
+	)
+
+	_, err := server.HandleTransaction(ctx, high)
+	require.Nil(t, err)
+
+	_, err = server.HandleTransaction(ctx, low)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = server.SubmitPackage(ctx, &blockchain.Package{Transactions: []*blockchain.Transaction{low}})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestHandleBlock(t *testing.T) {
+	var (
+		validator = NewServer(ServerConfig{PrivateKey: crypto.GeneratePrivateKey()}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		node      = NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		tx        = genesisSpend(t, validator.chain, 100)
+	)
+
+	require.Nil(t, validator.mempool.Add(tx))
+	require.Nil(t, node.mempool.Add(tx))
+
+	block, err := validator.createBlock()
+	require.Nil(t, err)
+
+	_, err = node.HandleBlock(context.Background(), block)
+	require.Nil(t, err)
+	require.Equal(t, 1, node.chain.Height())
+	require.Equal(t, 0, node.mempool.Len())
+
+	_, err = node.HandleBlock(context.Background(), block)
+	require.Nil(t, err)
+	require.Equal(t, 1, node.chain.Height())
+
+	_, err = node.HandleBlock(context.Background(), &blockchain.Block{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	invalid := util.RandomBlock()
+	invalid.Header.PreviousHash = types.HashBlock(block)
+	invalid.Header.Height = 2
+	types.SignBlock(crypto.GeneratePrivateKey(), invalid)
+	invalid.Header.RootHash = util.RandomHash()
+	_, err = node.HandleBlock(context.Background(), invalid)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, 1, node.chain.Height())
+	require.False(t, node.knownBlocks.Has(hex.EncodeToString(types.HashBlock(invalid))))
+
+	invalid.Header.Height = 5
+	types.SignBlock(crypto.GeneratePrivateKey(), invalid)
+	_, err = node.HandleBlock(context.Background(), invalid)
+	st := status.Convert(err)
+	require.Equal(t, codes.FailedPrecondition, st.Code())
+	require.Len(t, st.Details(), 1)
+	require.Equal(t, RuleHeight.String(), st.Details()[0].(*errdetails.ErrorInfo).Reason)
+}
+
+func TestHandleBlockKeepsOrphans(t *testing.T) {
+	var (
+		validator = newTestValidator(t, 3)
+		node      = NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+	)
+
+	blocks := []*blockchain.Block{}
+	for height := 1; height <= 3; height++ {
+		block, err := validator.chain.GetBlockByHeight(height)
+		require.Nil(t, err)
+		blocks = append(blocks, block)
+	}
+
+	for _, block := range []*blockchain.Block{blocks[2], blocks[1]} {
+		_, err := node.HandleBlock(context.Background(), block)
+		require.Nil(t, err)
+	}
+	require.Equal(t, 0, node.chain.Height())
+	require.Equal(t, 2, node.orphans.Len())
+
+	_, err := node.HandleBlock(context.Background(), blocks[0])
+	require.Nil(t, err)
+	require.Equal(t, 3, node.chain.Height())
+	require.Equal(t, 0, node.orphans.Len())
+}
+
+func TestHandleBlockDropsInvalidOrphans(t *testing.T) {
+	node := NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+
+	genesis, err := node.chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	var (
+		invalid = childBlock(genesis)
+		child   = childBlock(invalid)
+		orphan  = childBlock(child)
+	)
+	node.chain.Invalidate(types.HashBlock(invalid))
+
+	_, err = node.HandleBlock(context.Background(), orphan)
+	require.Nil(t, err)
+	require.Equal(t, 1, node.orphans.Len())
+
+	_, err = node.HandleBlock(context.Background(), child)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, 0, node.orphans.Len())
+	require.True(t, node.chain.IsInvalid(types.HashBlock(orphan)))
+
+	_, err = node.HandleBlock(context.Background(), orphan)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestReplaceByFeePropagation(t *testing.T) {
+	var (
+		config   = ServerConfig{Mempool: MempoolConfig{ReplaceByFee: true}}
+		sender   = NewServer(config, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		receiver = NewServer(config, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		original = genesisSpend(t, sender.chain, 100)
+		bump     = genesisSpend(t, sender.chain, 50)
+		address  = freeAddress(t)
+		ctx      = peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{}})
+	)
+
+	go receiver.Start(address, nil)
+
+	client, err := makeBlockChainClient(address)
+	require.Nil(t, err)
+	sender.addPeer(client, &blockchain.HandshakeMessage{ListenAddress: address})
+
+	_, err = sender.HandleTransaction(ctx, original)
+	require.Nil(t, err)
+	require.Eventually(t, func() bool {
+		return receiver.mempool.Has(original)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = sender.HandleTransaction(ctx, bump)
+	require.Nil(t, err)
+	require.False(t, sender.mempool.Has(original))
+	require.Eventually(t, func() bool {
+		return receiver.mempool.Has(bump) && !receiver.mempool.Has(original)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSubmitPackage(t *testing.T) {
+	var (
+		server     = NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		genesisKey = crypto.NewPrivateKeyFromString(seed)
+		ctx        = context.Background()
+	)
+
+	genesis, err := server.chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	parent := signedTx([]spend{{genesisKey, outPoint(genesis.Transactions[0], 0)}}, payTo(genesisKey, 999))
+	child := signedTx([]spend{{genesisKey, outPoint(parent, 0)}}, payTo(crypto.GeneratePrivateKey(), 900))
+
+	_, err = server.SubmitPackage(ctx, &blockchain.Package{Transactions: []*blockchain.Transaction{child, parent}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, 0, server.mempool.Len())
+
+	_, err = server.SubmitPackage(ctx, &blockchain.Package{Transactions: []*blockchain.Transaction{parent, child}})
+	require.Nil(t, err)
+	require.Equal(t, 2, server.mempool.Len())
+
+	_, err = server.SubmitPackage(ctx, &blockchain.Package{Transactions: []*blockchain.Transaction{parent, child}})
+	require.Nil(t, err)
+}
+
+func TestStopDumpsMempool(t *testing.T) {
+	var (
+		config = ServerConfig{MempoolFile: filepath.Join(t.TempDir(), "mempool.dat")}
+		server = NewServer(config, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		tx     = genesisSpend(t, server.chain, 100)
+	)
+
+	go server.Start(freeAddress(t), nil)
+	require.Nil(t, server.mempool.Add(tx))
+	require.Nil(t, server.Stop())
+	require.Nil(t, server.Stop())
+
+	restarted := NewServer(config, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+	go restarted.Start(freeAddress(t), nil)
+	defer restarted.Stop()
+
+	require.Eventually(t, func() bool {
+		return restarted.mempool.Has(tx)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestStopWaitsForLoops(t *testing.T) {
+	var (
+		chain  = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithGenesis(testWorkGenesis))
+		server = NewServer(ServerConfig{PrivateKey: crypto.GeneratePrivateKey()}, chain)
+		done   = make(chan struct{})
+	)
+
+	go server.Start(freeAddress(t), nil)
+	require.Eventually(t, func() bool { return chain.Height() > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// a loop still busy with a block when the server is asked to stop
+	server.startLoop(func() {
+		<-server.quit
+		time.Sleep(50 * time.Millisecond)
+		close(done)
+	})
+
+	require.Nil(t, server.Stop())
+	select {
+	case <-done:
+	default:
+		t.Fatal("Stop returned before the loops")
+	}
+
+	height := chain.Height()
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, height, chain.Height())
+
+	server.startLoop(func() { t.Error("loop started after Stop") })
+}
+
+func TestCreateBlockMinesOnProofOfWork(t *testing.T) {
+	var (
+		chain  = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithGenesis(testWorkGenesis))
+		server = NewServer(ServerConfig{PrivateKey: crypto.GeneratePrivateKey()}, chain)
+	)
+	require.Nil(t, server.finality)
+
+	block, err := server.createBlock()
+	require.Nil(t, err)
+	require.Equal(t, 1, chain.Height())
+	require.True(t, types.CheckWork(block.Header))
+	require.Nil(t, block.Signature)
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+)
+
+// offender returns the address of the validator that signed the headers of
+// the evidence.
+func offender(evidence *blockchain.Evidence) string {
+	return crypto.PublicKeyFromBytes(evidence.First.PublicKey).Address().String()
+}
+
+// Slashed returns the height at which the validator with the given hex
+// encoded address was slashed, and false when it was not.
+func (chain *Chain) Slashed(address string) (int, bool) {
+	chain.slashLock.RLock()
+	defer chain.slashLock.RUnlock()
+
+	height, ok := chain.slashed[address]
+	return height, ok
+}
+
+// validators returns the validators of the genesis set that were not slashed
+// below the given height, a validator slashed by a block leaves the set from
+// the next block on.
+func (chain *Chain) validators(height int) []*crypto.PublicKey {
+	validators := []*crypto.PublicKey{}
+	for _, validator := range chain.genesis.Validators {
+		if slashedAt, ok := chain.Slashed(validator.Address().String()); ok && slashedAt < height {
+			continue
+		}
+		validators = append(validators, validator)
+	}
+
+	return validators
+}
+
+// proposer returns the validator scheduled to propose the block at the given
+// height among the validators left, or nil when any key may propose it.
+func (chain *Chain) proposer(height int) *crypto.PublicKey {
+	if chain.genesis.Consensus != ConsensusAuthority || len(chain.genesis.Validators) == 0 {
+		return nil
+	}
+
+	validators := chain.validators(height)
+	return validators[height%len(validators)]
+}
+
+// validateEvidence checks an evidence transaction against the current tip, a
+// validator can only be slashed once. Evidence is only accepted where slashing
+// has an effect: against stakers, and against the members of a validator set,
+// which may not be emptied.
+func (chain *Chain) validateEvidence(tx *blockchain.Transaction) error {
+	if len(tx.Inputs) > 0 || len(tx.Outputs) > 0 {
+		return fmt.Errorf("evidence tx can not have inputs or outputs")
+	}
+
+	if tx.Evidence == nil || !types.VerifyEvidence(tx.Evidence) {
+		return fmt.Errorf("evidence tx does not prove a double signature")
+	}
+
+	address := offender(tx.Evidence)
+	if _, ok := chain.Slashed(address); ok {
+		return fmt.Errorf("validator %s is already slashed", address)
+	}
+
+	switch chain.genesis.Consensus {
+	case ConsensusStake:
+		if !chain.staked(address) {
+			return fmt.Errorf("%s has no stake to slash", address)
+		}
+	case ConsensusWork:
+		return fmt.Errorf("evidence has no effect on a proof-of-work network")
+	case ConsensusAuthority:
+		if len(chain.genesis.Validators) == 0 {
+			return fmt.Errorf("evidence has no effect on a network without a validator set")
+		}
+		if !chain.genesis.IsValidator(tx.Evidence.First.PublicKey) {
+			return fmt.Errorf("%s is not in the validator set", address)
+		}
+		if len(chain.validators(chain.Height()+1)) == 1 {
+			return fmt.Errorf("can not slash the last validator %s", address)
+		}
+	}
+
+	return nil
+}
+
+// burnedStake returns, as they are in the view, the staked outputs of the
+// validator convicted by the evidence transaction, which the block burns.
+func (chain *Chain) burnedStake(tx *blockchain.Transaction, view *utxoView) ([]*UTXO, error) {
+	address := offender(tx.Evidence)
+
+	utxos, err := chain.utxoStore.GetByAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	burned := []*UTXO{}
+	stored := make(map[types.OutPoint]bool)
+	for _, utxo := range utxos {
+		stored[utxo.OutPoint] = true
+
+		current, err := view.Get(utxo.OutPoint)
+		if err != nil {
+			return nil, err
+		}
+		if current.Staked && !current.Spent {
+			burned = append(burned, current)
+		}
+	}
+
+	// outputs staked earlier in the same block
+	for outPoint, utxo := range view.utxos {
+		if !stored[outPoint] && utxo.Address == address && utxo.Staked && !utxo.Spent {
+			burned = append(burned, utxo)
+		}
+	}
+
+	return burned, nil
+}
+
+// slash bars the validator convicted by the evidence transaction from staking
+// and proposing again.
+func (chain *Chain) slash(tx *blockchain.Transaction, height int) {
+	chain.slashLock.Lock()
+	defer chain.slashLock.Unlock()
+
+	chain.slashed[offender(tx.Evidence)] = height
+}
+
+// unslash forgets the validators slashed at the given height.
+func (chain *Chain) unslash(height int) {
+	chain.slashLock.Lock()
+	defer chain.slashLock.Unlock()
+
+	for address, slashedAt := range chain.slashed {
+		if slashedAt == height {
+			delete(chain.slashed, address)
+		}
+	}
+}
+
+// convictions holds the validators convicted by the evidence transactions of
+// a block, so that a block convicts a validator once, does not let it withdraw
+// the stake being burned and leaves at least one member in a validator set.
+type convictions struct {
+	addresses map[string]bool
+	// spare is how many more members of the validator set may be convicted,
+	// negative when the chain has no validator set.
+	spare int
+}
+
+func (chain *Chain) newConvictions() *convictions {
+	spare := -1
+	if chain.genesis.Consensus == ConsensusAuthority && len(chain.genesis.Validators) > 0 {
+		spare = len(chain.validators(chain.Height()+1)) - 1
+	}
+
+	return &convictions{addresses: make(map[string]bool), spare: spare}
+}
+
+// check checks a transaction validated against the view of the block so far
+// and records the validator it convicts.
+func (convicted *convictions) check(tx *blockchain.Transaction, view *utxoView) error {
+	switch tx.Kind {
+	case blockchain.Transaction_EVIDENCE:
+		address := offender(tx.Evidence)
+		if convicted.addresses[address] {
+			return fmt.Errorf("validator %s is convicted twice in the block", address)
+		}
+		if convicted.spare == 0 {
+			return fmt.Errorf("block can not slash every validator")
+		}
+		convicted.addresses[address] = true
+		if convicted.spare > 0 {
+			convicted.spare--
+		}
+	case blockchain.Transaction_UNSTAKE:
+		for i, input := range tx.Inputs {
+			utxo, err := view.Get(types.SpentOutPoint(input))
+			if err != nil {
+				return err
+			}
+			if convicted.addresses[utxo.Address] {
+				return fmt.Errorf("input %d of unstake tx withdraws the stake of a slashed validator", i)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/peer"
+)
+
+// doubleSign returns two different blocks at the next height signed by key.
+func doubleSign(t *testing.T, chain *Chain, key *crypto.PrivateKey) (*blockchain.Block, *blockchain.Block) {
+	first, err := chain.NewBlock(nil, nil)
+	require.Nil(t, err)
+	types.SignBlock(key, first)
+
+	second, err := chain.NewBlock(nil, nil)
+	require.Nil(t, err)
+	second.Header.Timestamp = first.Header.Timestamp + 1
+	types.SignBlock(key, second)
+
+	return first, second
+}
+
+func TestSlashDoubleSigner(t *testing.T) {
+	var (
+		fixture = newConsensusFixture(t, WithGenesis(Genesis{Consensus: ConsensusStake}))
+		chain   = fixture.chain
+		stake   = signedKindTx(blockchain.Transaction_STAKE, []spend{{fixture.alice, outPoint(fixture.split, 0)}}, payTo(fixture.alice, 300))
+	)
+
+	require.Nil(t, mineBlock(chain, stake))
+	supply, err := chain.Supply()
+	require.Nil(t, err)
+
+	first, second := doubleSign(t, chain, fixture.alice)
+	evidence := types.NewEvidenceTransaction(first, second)
+
+	forged := types.NewEvidenceTransaction(first, second)
+	forged.Evidence.Second = types.SignedHeaderOf(first)
+	_, third := doubleSign(t, chain, fixture.alice)
+	unstaked := types.NewEvidenceTransaction(doubleSign(t, chain, fixture.bob))
+
+	cases := map[string][]*blockchain.Transaction{
+		"same header twice":     {forged},
+		"convicted twice":       {evidence, types.NewEvidenceTransaction(first, third)},
+		"unstake after":         {evidence, signedKindTx(blockchain.Transaction_UNSTAKE, []spend{{fixture.alice, outPoint(stake, 0)}}, payTo(fixture.alice, 300))},
+		"offender never staked": {unstaked},
+		"evidence with input":   {signedKindTx(blockchain.Transaction_EVIDENCE, []spend{{fixture.bob, outPoint(fixture.split, 1)}})},
+	}
+	for name, transactions := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.NotNil(t, proposeStakeBlock(t, chain, fixture.alice, transactions...))
+		})
+	}
+
+	require.Nil(t, proposeStakeBlock(t, chain, fixture.alice, evidence))
+	require.Empty(t, chain.Stakes())
+	require.Equal(t, int64(0), balance(t, chain, fixture.alice))
+
+	height, ok := chain.Slashed(address(fixture.alice))
+	require.True(t, ok)
+	require.Equal(t, 3, height)
+
+	burned, err := chain.Supply()
+	require.Nil(t, err)
+	require.Equal(t, supply.Circulating-300, burned.Circulating)
+
+	// the offender can neither be convicted again, stake again nor propose
+	require.NotNil(t, mineBlock(chain, types.NewEvidenceTransaction(first, third)))
+	restake := signedKindTx(blockchain.Transaction_STAKE, []spend{{fixture.bob, outPoint(fixture.split, 1)}}, payTo(fixture.alice, 700))
+	require.NotNil(t, mineBlock(chain, restake))
+	require.NotNil(t, proposeStakeBlock(t, chain, fixture.alice))
+
+	reopened, err := OpenChain(chain.blockStore, chain.txStore, chain.utxoStore, WithGenesis(Genesis{Consensus: ConsensusStake}))
+	require.Nil(t, err)
+	height, ok = reopened.Slashed(address(fixture.alice))
+	require.True(t, ok)
+	require.Equal(t, 3, height)
+
+	require.Nil(t, chain.DisconnectTip())
+	_, ok = chain.Slashed(address(fixture.alice))
+	require.False(t, ok)
+	require.Equal(t, map[string]int64{address(fixture.alice): 300}, chain.Stakes())
+}
+
+func proposeStakeBlock(t *testing.T, chain *Chain, key *crypto.PrivateKey, transactions ...*blockchain.Transaction) error {
+	block, err := chain.NewBlock(nil, transactions)
+	require.Nil(t, err)
+	types.SignBlock(key, block)
+
+	return chain.AddBlock(block)
+}
+
+func TestSlashedValidatorLeavesTheSet(t *testing.T) {
+	var (
+		keys, genesis = newValidatorSet(3)
+		chain         = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithGenesis(genesis))
+		server        = NewServer(ServerConfig{PrivateKey: keys[1]}, chain)
+		ctx           = peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{}})
+	)
+
+	first, second := doubleSign(t, chain, keys[2])
+	evidence := types.NewEvidenceTransaction(first, second)
+
+	// evidence pays no fee but goes into the next block first
+	_, err := server.HandleTransaction(ctx, genesisSpend(t, chain, 100))
+	require.Nil(t, err)
+	_, err = server.HandleTransaction(ctx, evidence)
+	require.Nil(t, err)
+	require.Equal(t, []*blockchain.Transaction{evidence}, server.mempool.Select(1 << 20)[:1])
+
+	_, third := doubleSign(t, chain, keys[2])
+	_, err = server.HandleTransaction(ctx, types.NewEvidenceTransaction(first, third))
+	require.NotNil(t, err)
+
+	block, err := server.createBlock()
+	require.Nil(t, err)
+	require.Len(t, block.Transactions, 3)
+	require.Equal(t, 0, server.mempool.Len())
+
+	height, ok := chain.Slashed(address(keys[2]))
+	require.True(t, ok)
+	require.Equal(t, 1, height)
+
+	// the schedule goes on with the validators left
+	require.Equal(t, keys[0].Public(), chain.Proposer(2))
+	require.Equal(t, keys[1].Public(), chain.Proposer(3))
+	require.False(t, chain.CanPropose(keys[2].Public()))
+
+	slashed, err := chain.NewBlock(nil, nil)
+	require.Nil(t, err)
+	types.SignBlock(keys[2], slashed)
+	require.NotNil(t, chain.AddBlock(slashed))
+
+	next := proposeBlock(t, []*crypto.PrivateKey{keys[0], keys[1]}, chain)
+	hash := types.HashBlock(next)
+
+	// nor does the slashed validator vote or count towards a quorum
+	engine := newFinality(chain, keys[0])
+	require.NotNil(t, engine.AddVote(signedVote(keys[2], blockchain.Vote_PREVOTE, 2, hash)))
+	require.NotNil(t, chain.Finalize(&blockchain.Commit{
+		Height:    2,
+		BlockHash: hash,
+		Precommits: []*blockchain.Vote{
+			signedVote(keys[0], blockchain.Vote_PRECOMMIT, 2, hash),
+			signedVote(keys[2], blockchain.Vote_PRECOMMIT, 2, hash),
+		},
+	}))
+	require.Nil(t, chain.Finalize(&blockchain.Commit{
+		Height:    2,
+		BlockHash: hash,
+		Precommits: []*blockchain.Vote{
+			signedVote(keys[0], blockchain.Vote_PRECOMMIT, 2, hash),
+			signedVote(keys[1], blockchain.Vote_PRECOMMIT, 2, hash),
+		},
+	}))
+}
+
+func TestEvidenceWithoutEffectIsRejected(t *testing.T) {
+	keys, genesis := newValidatorSet(2)
+
+	cases := map[string]Genesis{
+		"open network":       {},
+		"without stake":      {Consensus: ConsensusStake},
+		"proof of work":      {Consensus: ConsensusWork},
+		"last validator":     {Validators: genesis.Validators[:1]},
+		"outside of the set": {Validators: genesis.Validators[1:]},
+	}
+	for name, genesis := range cases {
+		t.Run(name, func(t *testing.T) {
+			chain := NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithGenesis(genesis))
+			first, second := doubleSign(t, chain, keys[0])
+
+			require.NotNil(t, NewMempool(chain, MempoolConfig{}).Add(types.NewEvidenceTransaction(first, second)))
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+)
+
+// Stakes returns the bonded stake of every address with a stake, by hex
+// encoded address.
+func (chain *Chain) Stakes() map[string]int64 {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	stakes := make(map[string]int64, len(chain.stakes))
+	for address, amount := range chain.stakes {
+		stakes[address] = amount
+	}
+
+	return stakes
+}
+
+// trackStake adds the amount of a staked output to the stake of its owner,
+// or removes it when sign is negative.
+func (chain *Chain) trackStake(utxo *UTXO, sign int64) {
+	if !utxo.Staked || utxo.Spent {
+		return
+	}
+
+	chain.stakeLock.Lock()
+	defer chain.stakeLock.Unlock()
+
+	chain.stakes[utxo.Address] += sign * utxo.Amount
+	if chain.stakes[utxo.Address] == 0 {
+		delete(chain.stakes, utxo.Address)
+	}
+}
+
+// staked reports whether the address has a bonded stake.
+func (chain *Chain) staked(address string) bool {
+	chain.stakeLock.RLock()
+	defer chain.stakeLock.RUnlock()
+
+	return chain.stakes[address] > 0
+}
+
+func (chain *Chain) loadStakes() error {
+	chain.stakeLock.Lock()
+	chain.stakes = make(map[string]int64)
+	chain.stakeLock.Unlock()
+
+	return chain.utxoStore.ForEach(func(utxo *UTXO) error {
+		chain.trackStake(utxo, 1)
+		return nil
+	})
+}
+
+// stakeProposer returns the address allowed to propose the block following
+// the block with the given hash, drawn from the current stakes. It is empty
+// when nothing is staked.
+func (chain *Chain) stakeProposer(previousHash []byte) string {
+	return drawStaker(chain.stakes, previousHash)
+}
+
+// drawStaker picks an address with a probability proportional to its stake,
+// using the hash of the previous block as the seed. It is empty when nothing
+// is staked.
+func drawStaker(stakes map[string]int64, previousHash []byte) string {
+	addresses := make([]string, 0, len(stakes))
+	var total int64
+	for address, amount := range stakes {
+		addresses = append(addresses, address)
+		total += amount
+	}
+	if total <= 0 {
+		return ""
+	}
+	sort.Strings(addresses)
+
+	seed := sha256.Sum256(previousHash)
+	draw := new(big.Int).SetBytes(seed[:])
+	pick := draw.Mod(draw, big.NewInt(total)).Int64()
+
+	for _, address := range addresses {
+		if pick < stakes[address] {
+			return address
+		}
+		pick -= stakes[address]
+	}
+
+	return addresses[len(addresses)-1]
+}
+
+// stakesAt returns the stakes as they are after the block of node, which may
+// be on a side branch. The stakes of the main chain are rolled back to the
+// fork and then forward along the branch.
+func (chain *Chain) stakesAt(node *BlockNode) (map[string]int64, error) {
+	if node == chain.tip {
+		return chain.stakes, nil
+	}
+
+	stakes := make(map[string]int64, len(chain.stakes))
+	for address, amount := range chain.stakes {
+		stakes[address] = amount
+	}
+	track := func(utxo *UTXO, sign int64) {
+		if !utxo.Staked {
+			return
+		}
+		stakes[utxo.Address] += sign * utxo.Amount
+		if stakes[utxo.Address] == 0 {
+			delete(stakes, utxo.Address)
+		}
+	}
+
+	fork := findFork(node, chain.tip)
+	for current := chain.tip; current != fork; current = current.Parent {
+		block, err := chain.blockStore.Get(current.Hash)
+		if err != nil {
+			return nil, err
+		}
+		undo, err := chain.blockStore.GetUndo(current.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, utxo := range undo.Spent {
+			track(utxo, 1)
+		}
+		for _, tx := range block.Transactions {
+			hash := types.HashTransaction(tx)
+			for index := range tx.Outputs {
+				track(outputUTXO(tx, hash, index), -1)
+			}
+		}
+	}
+
+	branch := []*BlockNode{}
+	for current := node; current != fork; current = current.Parent {
+		branch = append(branch, current)
+	}
+
+	// the outputs spent along the branch are either created by it or stored,
+	// spent or not by the main chain
+	created := make(map[types.OutPoint]*UTXO)
+	for i := len(branch) - 1; i >= 0; i-- {
+		block, err := chain.blockStore.Get(branch[i].Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tx := range block.Transactions {
+			if tx.Kind == blockchain.Transaction_EVIDENCE && tx.Evidence != nil {
+				delete(stakes, offender(tx.Evidence))
+			}
+
+			for _, input := range tx.Inputs {
+				if types.IsCoinbase(tx) {
+					break
+				}
+				outPoint := types.SpentOutPoint(input)
+				utxo, ok := created[outPoint]
+				if !ok {
+					if utxo, err = chain.utxoStore.Get(outPoint); err != nil {
+						return nil, err
+					}
+				}
+				track(utxo, -1)
+			}
+
+			hash := types.HashTransaction(tx)
+			for index := range tx.Outputs {
+				utxo := outputUTXO(tx, hash, index)
+				created[utxo.OutPoint] = utxo
+				track(utxo, 1)
+			}
+		}
+	}
+
+	return stakes, nil
+}
+
+// validateStaker checks that a block extending parent is signed by the staker
+// drawn for it from the stakes on the branch of parent.
+func (chain *Chain) validateStaker(block *blockchain.Block, parent *BlockNode) error {
+	if chain.genesis.Consensus != ConsensusStake {
+		return nil
+	}
+
+	signer := crypto.PublicKeyFromBytes(block.PublicKey).Address().String()
+	if _, ok := chain.Slashed(signer); ok {
+		return fmt.Errorf("block is signed by the slashed validator %s", signer)
+	}
+
+	stakes, err := chain.stakesAt(parent)
+	if err != nil {
+		return err
+	}
+
+	staker := drawStaker(stakes, block.Header.PreviousHash)
+	if staker == "" {
+		return nil
+	}
+
+	if signer != staker {
+		return fmt.Errorf("block is signed by %s instead of the drawn staker %s", signer, staker)
+	}
+
+	return nil
+}
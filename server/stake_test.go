@@ -0,0 +1,141 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/blockchain/util"
+	"github.com/stretchr/testify/require"
+)
+
+func address(key *crypto.PrivateKey) string {
+	return key.Public().Address().String()
+}
+
+func TestStakeLocksOutputs(t *testing.T) {
+	var (
+		fixture = newConsensusFixture(t)
+		chain   = fixture.chain
+		stake   = signedKindTx(blockchain.Transaction_STAKE, []spend{{fixture.alice, outPoint(fixture.split, 0)}}, payTo(fixture.alice, 290))
+		staked  = outPoint(stake, 0)
+	)
+
+	require.Nil(t, mineBlock(chain, stake))
+	require.Equal(t, map[string]int64{address(fixture.alice): 290}, chain.Stakes())
+
+	cases := map[string]*blockchain.Transaction{
+		"transfer of a staked output": signedTx([]spend{{fixture.alice, staked}}, payTo(fixture.alice, 280)),
+		"unstake of a regular output": signedKindTx(blockchain.Transaction_UNSTAKE, []spend{{fixture.bob, outPoint(fixture.split, 1)}}, payTo(fixture.bob, 690)),
+		"stake without outputs":       signedKindTx(blockchain.Transaction_STAKE, []spend{{fixture.bob, outPoint(fixture.split, 1)}}),
+	}
+	for name, tx := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.NotNil(t, mineBlock(chain, tx))
+		})
+	}
+
+	unstake := signedKindTx(blockchain.Transaction_UNSTAKE, []spend{{fixture.alice, staked}}, payTo(fixture.alice, 280))
+	require.Nil(t, mineBlock(chain, unstake))
+	require.Empty(t, chain.Stakes())
+	require.Equal(t, int64(280), balance(t, chain, fixture.alice))
+
+	require.Nil(t, chain.DisconnectTip())
+	require.Equal(t, map[string]int64{address(fixture.alice): 290}, chain.Stakes())
+
+	reopened, err := OpenChain(chain.blockStore, chain.txStore, chain.utxoStore)
+	require.Nil(t, err)
+	require.Equal(t, map[string]int64{address(fixture.alice): 290}, reopened.Stakes())
+
+	require.Nil(t, chain.DisconnectTip())
+	require.Empty(t, chain.Stakes())
+}
+
+func TestStakeWeightedProposer(t *testing.T) {
+	var (
+		genesisKey = crypto.NewPrivateKeyFromString(seed)
+		alice      = crypto.GeneratePrivateKey()
+		bob        = crypto.GeneratePrivateKey()
+		chain      = NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithGenesis(Genesis{Consensus: ConsensusStake}))
+		keys       = map[string]*crypto.PrivateKey{address(alice): alice, address(bob): bob}
+	)
+
+	genesis, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	// any key may sign while nothing is staked
+	require.True(t, chain.CanPropose(crypto.GeneratePrivateKey().Public()))
+	split := signedTx([]spend{{genesisKey, outPoint(genesis.Transactions[0], 0)}}, payTo(alice, 250), payTo(bob, 750))
+	require.Nil(t, mineBlock(chain, split))
+	stake := signedKindTx(blockchain.Transaction_STAKE, []spend{{alice, outPoint(split, 0)}, {bob, outPoint(split, 1)}}, payTo(alice, 250), payTo(bob, 750))
+	require.Nil(t, mineBlock(chain, stake))
+
+	for i := 0; i < 5; i++ {
+		tip, err := chain.GetHeaderByHeight(chain.Height())
+		require.Nil(t, err)
+
+		drawn := keys[chain.stakeProposer(types.HashHeader(tip))]
+		require.NotNil(t, drawn)
+		require.True(t, chain.CanPropose(drawn.Public()))
+
+		block, err := chain.NewBlock(nil, nil)
+		require.Nil(t, err)
+		for _, key := range []*crypto.PrivateKey{alice, bob, genesisKey} {
+			if key == drawn {
+				continue
+			}
+			require.False(t, chain.CanPropose(key.Public()))
+			types.SignBlock(key, block)
+			require.NotNil(t, chain.AddBlock(block))
+		}
+
+		types.SignBlock(drawn, block)
+		require.Nil(t, chain.AddBlock(block))
+	}
+
+	picks := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		picks[chain.stakeProposer(util.RandomHash())]++
+	}
+	require.InDelta(t, 250, picks[address(alice)], 60)
+	require.InDelta(t, 750, picks[address(bob)], 60)
+}
+
+func TestStakeDrawOnSideBranch(t *testing.T) {
+	var (
+		fixture = newConsensusFixture(t, WithGenesis(Genesis{Consensus: ConsensusStake}))
+		chain   = fixture.chain
+		stake   = signedKindTx(blockchain.Transaction_STAKE, []spend{{fixture.alice, outPoint(fixture.split, 0)}}, payTo(fixture.alice, 300))
+		bonded  = signedKindTx(blockchain.Transaction_STAKE, []spend{{fixture.bob, outPoint(fixture.split, 1)}}, payTo(fixture.bob, 700))
+	)
+
+	require.Nil(t, mineBlock(chain, stake))
+	require.Nil(t, proposeStakeBlock(t, chain, fixture.alice))
+
+	split, err := chain.GetBlockByHeight(1)
+	require.Nil(t, err)
+	staked, err := chain.GetBlockByHeight(2)
+	require.Nil(t, err)
+
+	signed := func(key *crypto.PrivateKey, block *blockchain.Block) *blockchain.Block {
+		types.SignBlock(key, block)
+		return block
+	}
+
+	// alice has staked everything on this branch
+	require.NotNil(t, chain.AddBlock(signed(fixture.bob, childBlock(staked))))
+
+	// nothing is staked below her stake, then bob stakes on his branch and is
+	// the only one drawn on it
+	first := signed(fixture.bob, childBlock(split))
+	require.Nil(t, chain.AddBlock(first))
+	second := signed(fixture.bob, childBlock(first, bonded))
+	require.Nil(t, chain.AddBlock(second))
+	require.Equal(t, 3, chain.Height())
+
+	require.NotNil(t, chain.AddBlock(signed(fixture.alice, childBlock(second))))
+	require.Nil(t, chain.AddBlock(signed(fixture.bob, childBlock(second))))
+	require.Equal(t, 4, chain.Height())
+	require.Equal(t, map[string]int64{address(fixture.bob): 700}, chain.Stakes())
+}
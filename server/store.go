@@ -10,28 +10,61 @@ import (
 )
 
 type UTXOStorer interface {
-	Get(hash string) (*UTXO, error)
+	Get(outPoint types.OutPoint) (*UTXO, error)
 	Put(utxo *UTXO) error
+	Delete(outPoint types.OutPoint) error
+	// GetByAddress returns every stored output, spent or not, paying to the
+	// hex encoded address.
+	GetByAddress(address string) ([]*UTXO, error)
+	// ForEach calls fn with every stored output, spent or not. fn must not
+	// write to the store.
+	ForEach(fn func(utxo *UTXO) error) error
+}
+
+// addressIndex maps an address to the outputs paying to it.
+type addressIndex map[string]map[types.OutPoint]struct{}
+
+func (index addressIndex) add(address string, outPoint types.OutPoint) {
+	outPoints, ok := index[address]
+	if !ok {
+		outPoints = make(map[types.OutPoint]struct{})
+		index[address] = outPoints
+	}
+	outPoints[outPoint] = struct{}{}
+}
+
+func (index addressIndex) remove(address string, outPoint types.OutPoint) {
+	outPoints, ok := index[address]
+	if !ok {
+		return
+	}
+
+	delete(outPoints, outPoint)
+	if len(outPoints) == 0 {
+		delete(index, address)
+	}
 }
 
 type MemoryUTXOStore struct {
-	lock sync.RWMutex
-	data map[string]*UTXO
+	lock      sync.RWMutex
+	data      map[types.OutPoint]*UTXO
+	addresses addressIndex
 }
 
 func NewMemoryUTXOStore() *MemoryUTXOStore {
 	return &MemoryUTXOStore{
-		data: make(map[string]*UTXO),
+		data:      make(map[types.OutPoint]*UTXO),
+		addresses: make(addressIndex),
 	}
 }
 
-func (store *MemoryUTXOStore) Get(hash string) (*UTXO, error) {
+func (store *MemoryUTXOStore) Get(outPoint types.OutPoint) (*UTXO, error) {
 	store.lock.RLock()
 	defer store.lock.RUnlock()
 
-	utxo, ok := store.data[hash]
+	utxo, ok := store.data[outPoint]
 	if !ok {
-		return nil, fmt.Errorf("could not find utxo with hash %s", hash)
+		return nil, fmt.Errorf("could not find utxo %s", outPoint)
 	}
 
 	return utxo, nil
@@ -41,8 +74,45 @@ func (store *MemoryUTXOStore) Put(utxo *UTXO) error {
 	store.lock.Lock()
 	defer store.lock.Unlock()
 
-	key := fmt.Sprintf("%s_%d", utxo.Hash, utxo.OutIndex)
-	store.data[key] = utxo
+	store.data[utxo.OutPoint] = utxo
+	store.addresses.add(utxo.Address, utxo.OutPoint)
+
+	return nil
+}
+
+func (store *MemoryUTXOStore) Delete(outPoint types.OutPoint) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	if utxo, ok := store.data[outPoint]; ok {
+		store.addresses.remove(utxo.Address, outPoint)
+	}
+	delete(store.data, outPoint)
+
+	return nil
+}
+
+func (store *MemoryUTXOStore) GetByAddress(address string) ([]*UTXO, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	utxos := []*UTXO{}
+	for outPoint := range store.addresses[address] {
+		utxos = append(utxos, store.data[outPoint])
+	}
+
+	return utxos, nil
+}
+
+func (store *MemoryUTXOStore) ForEach(fn func(utxo *UTXO) error) error {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	for _, utxo := range store.data {
+		if err := fn(utxo); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -50,6 +120,7 @@ func (store *MemoryUTXOStore) Put(utxo *UTXO) error {
 type TXStorer interface {
 	Put(*blockchain.Transaction) error
 	Get(hash string) (*blockchain.Transaction, error)
+	Delete(hash string) error
 }
 
 type MemoryTxStore struct {
@@ -85,19 +156,73 @@ func (store *MemoryTxStore) Get(hash string) (*blockchain.Transaction, error) {
 	return tx, nil
 }
 
+func (store *MemoryTxStore) Delete(hash string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	delete(store.txx, hash)
+	return nil
+}
+
+// syncer is implemented by the stores that may hold writes back from the
+// disk, Sync makes the writes so far durable.
+type syncer interface {
+	Sync() error
+}
+
+// syncStores makes the writes to the stores durable, the chain calls it
+// wherever the order in which its stores reach the disk matters.
+func syncStores(stores ...any) error {
+	for _, store := range stores {
+		if store, ok := store.(syncer); ok {
+			if err := store.Sync(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 type BlockStorer interface {
 	Put(block *blockchain.Block) error
 	Get(hash string) (*blockchain.Block, error)
+	// Tip returns the hash of the last block of the main chain, or an empty
+	// string if the store does not hold a chain yet.
+	Tip() (string, error)
+	SetTip(hash string) error
+	// Pending returns the hash of the block the chain was connecting or
+	// disconnecting, or an empty string once the change is complete.
+	Pending() (string, error)
+	SetPending(hash string) error
+	PutUndo(hash string, undo *BlockUndo) error
+	GetUndo(hash string) (*BlockUndo, error)
+	PutCommit(hash string, commit *blockchain.Commit) error
+	// GetCommit returns nil without an error when the block has no commit.
+	GetCommit(hash string) (*blockchain.Commit, error)
+}
+
+// BlockUndo holds what is needed to revert a connected block: the outputs it
+// spent as they were before the block and the outputs it created.
+type BlockUndo struct {
+	Spent   []*UTXO
+	Created []types.OutPoint
 }
 
 type MemoryBlockStore struct {
-	lock   sync.RWMutex
-	blocks map[string]*blockchain.Block
+	lock    sync.RWMutex
+	blocks  map[string]*blockchain.Block
+	undos   map[string]*BlockUndo
+	commits map[string]*blockchain.Commit
+	tip     string
+	pending string
 }
 
 func NewMemoryBlockStore() *MemoryBlockStore {
 	return &MemoryBlockStore{
-		blocks: make(map[string]*blockchain.Block),
+		blocks:  make(map[string]*blockchain.Block),
+		undos:   make(map[string]*BlockUndo),
+		commits: make(map[string]*blockchain.Commit),
 	}
 }
 
@@ -121,3 +246,68 @@ func (store *MemoryBlockStore) Get(hash string) (*blockchain.Block, error) {
 
 	return block, nil
 }
+
+func (store *MemoryBlockStore) Tip() (string, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	return store.tip, nil
+}
+
+func (store *MemoryBlockStore) SetTip(hash string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.tip = hash
+	return nil
+}
+
+func (store *MemoryBlockStore) Pending() (string, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	return store.pending, nil
+}
+
+func (store *MemoryBlockStore) SetPending(hash string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.pending = hash
+	return nil
+}
+
+func (store *MemoryBlockStore) PutUndo(hash string, undo *BlockUndo) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.undos[hash] = undo
+	return nil
+}
+
+func (store *MemoryBlockStore) GetUndo(hash string) (*BlockUndo, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	undo, ok := store.undos[hash]
+	if !ok {
+		return nil, fmt.Errorf("undo data for block [%s] does not exists", hash)
+	}
+
+	return undo, nil
+}
+
+func (store *MemoryBlockStore) PutCommit(hash string, commit *blockchain.Commit) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.commits[hash] = commit
+	return nil
+}
+
+func (store *MemoryBlockStore) GetCommit(hash string) (*blockchain.Commit, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	return store.commits[hash], nil
+}
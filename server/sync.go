@@ -0,0 +1,375 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxHeadersPerRequest = 2000
+	blocksPerRequest     = 16
+	blocksPerWindow      = 256
+)
+
+func (server *Server) GetHeaders(request *blockchain.GetHeadersRequest, stream blockchain.BlockChain_GetHeadersServer) error {
+	limit := int(request.Limit)
+	if limit <= 0 || limit > maxHeadersPerRequest {
+		limit = maxHeadersPerRequest
+	}
+
+	height := int(request.FromHeight)
+	if len(request.Locator) > 0 {
+		height = server.chain.LocateFork(request.Locator) + 1
+	}
+
+	for sent := 0; sent < limit; sent++ {
+		header, err := server.chain.GetHeaderByHeight(height + sent)
+		if err != nil {
+			break
+		}
+
+		if err := stream.Send(header); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (server *Server) GetBlocks(request *blockchain.GetBlocksRequest, stream blockchain.BlockChain_GetBlocksServer) error {
+	for _, hash := range request.Hashes {
+		block, err := server.chain.GetBlockByHash(hash)
+		if err != nil {
+			return status.Errorf(codes.NotFound, "block %s not found", hex.EncodeToString(hash))
+		}
+
+		if err := stream.Send(block); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncWithPeers brings the chain up to the height advertised by the best peer.
+// Headers are downloaded first and checked against the local header list, then
+// the block bodies are fetched in parallel from every peer that is ahead of us.
+func (server *Server) syncWithPeers() {
+	if !server.syncLock.TryLock() {
+		return
+	}
+	defer server.syncLock.Unlock()
+
+	for {
+		peer, height := server.bestPeer()
+		if peer == nil || height <= server.chain.Height() {
+			return
+		}
+
+		headers, err := server.downloadHeaders(peer)
+		if err != nil {
+			server.logger.Errorw("header download failed", "err", err)
+			return
+		}
+		if len(headers) == 0 {
+			return
+		}
+
+		server.logger.Debugw("downloaded headers", "count", len(headers), "we", server.ListenAddress)
+
+		if err := server.downloadBlocks(headers); err != nil {
+			server.logger.Errorw("block download failed", "err", err)
+			return
+		}
+	}
+}
+
+// syncCommits fetches from the peers the commits of the heights above our
+// finalized block, after catching up with their blocks.
+func (server *Server) syncCommits() {
+	if !server.commitSyncLock.TryLock() {
+		return
+	}
+	defer server.commitSyncLock.Unlock()
+
+	server.syncWithPeers()
+
+	for _, peer := range server.getPeers() {
+		for {
+			height := server.chain.Finalized().Height + 1
+			commit, err := peer.GetCommit(context.Background(), &blockchain.GetCommitRequest{Height: int32(height)})
+			if err != nil {
+				break
+			}
+
+			if err := server.chain.Finalize(commit); err != nil {
+				server.logger.Errorw("rejected commit", "height", height, "err", err)
+				break
+			}
+		}
+	}
+
+	server.finality.Wake()
+	server.broadcastVotes(server.finality.Votes())
+}
+
+func (server *Server) bestPeer() (blockchain.BlockChainClient, int) {
+	server.peerLock.RLock()
+	defer server.peerLock.RUnlock()
+
+	var (
+		best   blockchain.BlockChainClient
+		height = -1
+	)
+	for peer, version := range server.peers {
+		if int(version.Height) > height {
+			best = peer
+			height = int(version.Height)
+		}
+	}
+
+	return best, height
+}
+
+// updatePeerHeight raises the height we know the peer to be at, the one it
+// advertised in the handshake grows stale as it receives blocks.
+func (server *Server) updatePeerHeight(peer blockchain.BlockChainClient, height int) {
+	server.peerLock.Lock()
+	defer server.peerLock.Unlock()
+
+	if version, ok := server.peers[peer]; ok && int(version.Height) < height {
+		version.Height = int32(height)
+	}
+}
+
+// updateSenderHeight raises the height of the peer a block came from, found by
+// the listen address it sends along with its broadcasts. The sender controls
+// that address, so it only counts when it points at the host the connection
+// came from, and a sender cannot raise the height of a peer on another host.
+func (server *Server) updateSenderHeight(ctx context.Context, height int) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	addresses := md.Get(listenAddressKey)
+	if len(addresses) == 0 || !sentFrom(ctx, addresses[0]) {
+		return
+	}
+
+	server.peerLock.Lock()
+	defer server.peerLock.Unlock()
+
+	for _, version := range server.peers {
+		if version.ListenAddress == addresses[0] && int(version.Height) < height {
+			version.Height = int32(height)
+		}
+	}
+}
+
+// sentFrom reports whether the listen address is on the host the request
+// came from. An address without a host is only reachable locally.
+func sentFrom(ctx context.Context, listenAddress string) bool {
+	sender, ok := peer.FromContext(ctx)
+	if !ok || sender.Addr == nil {
+		return false
+	}
+	senderHost, _, err := net.SplitHostPort(sender.Addr.String())
+	if err != nil {
+		return false
+	}
+	senderIP := net.ParseIP(senderHost)
+	if senderIP == nil {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(listenAddress)
+	if err != nil {
+		return false
+	}
+	if host == "" {
+		return senderIP.IsLoopback()
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.Equal(senderIP) || (ip.IsUnspecified() && senderIP.IsLoopback())
+	}
+
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+	if err != nil {
+		return false
+	}
+	for _, ip := range ips {
+		if ip.Equal(senderIP) {
+			return true
+		}
+	}
+	return false
+}
+
+func (server *Server) peersAbove(height int) []blockchain.BlockChainClient {
+	server.peerLock.RLock()
+	defer server.peerLock.RUnlock()
+
+	peers := []blockchain.BlockChainClient{}
+	for peer, version := range server.peers {
+		if int(version.Height) > height {
+			peers = append(peers, peer)
+		}
+	}
+
+	return peers
+}
+
+// downloadHeaders asks the peer for the headers following the last block our
+// main chain shares with its own, and returns the ones we do not have yet. The
+// first of them links to a block we know, which need not be our tip when the
+// peer is on another branch.
+func (server *Server) downloadHeaders(peer blockchain.BlockChainClient) ([]*blockchain.Header, error) {
+	stream, err := peer.GetHeaders(context.Background(), &blockchain.GetHeadersRequest{
+		FromHeight: int32(server.chain.Height() + 1),
+		Limit:      maxHeadersPerRequest,
+		Locator:    server.chain.Locator(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	headers := []*blockchain.Header{}
+	var previousHash []byte
+	for {
+		header, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			return nil, fmt.Errorf("peer sent an empty header")
+		}
+
+		switch {
+		case previousHash == nil && !server.chain.HasBlock(header.PreviousHash):
+			return nil, fmt.Errorf("header at height %d does not link to a known block", header.Height)
+		case previousHash != nil && !bytes.Equal(header.PreviousHash, previousHash):
+			return nil, fmt.Errorf("header at height %d does not link to the previous header", header.Height)
+		}
+		previousHash = types.HashHeader(header)
+
+		server.updatePeerHeight(peer, int(header.Height))
+
+		// the locator is sparse, the first headers may be known already
+		if len(headers) == 0 && server.chain.HasBlock(previousHash) {
+			continue
+		}
+		headers = append(headers, header)
+	}
+
+	return headers, nil
+}
+
+// downloadBlocks fetches the bodies of the given headers window by window,
+// spreading the requests of a window over the available peers, and connects
+// them to the chain in order.
+func (server *Server) downloadBlocks(headers []*blockchain.Header) error {
+	for start := 0; start < len(headers); start += blocksPerWindow {
+		end := min(start+blocksPerWindow, len(headers))
+
+		blocks, err := server.fetchBlocks(headers[start:end])
+		if err != nil {
+			return err
+		}
+
+		for _, block := range blocks {
+			if err := server.chain.AddBlock(block); err != nil {
+				server.dropInvalidOrphans(types.HashBlock(block))
+				return err
+			}
+
+			hash := hex.EncodeToString(types.HashBlock(block))
+			server.knownBlocks.Add(hash)
+			server.connectOrphans(hash)
+		}
+	}
+
+	return nil
+}
+
+func (server *Server) fetchBlocks(headers []*blockchain.Header) ([]*blockchain.Block, error) {
+	peers := server.peersAbove(server.chain.Height())
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("no peers to download blocks from")
+	}
+
+	var (
+		blocks = make([]*blockchain.Block, len(headers))
+		errs   = make([]error, 0)
+		lock   sync.Mutex
+		wg     sync.WaitGroup
+	)
+
+	for batch, start := 0, 0; start < len(headers); batch, start = batch+1, start+blocksPerRequest {
+		end := min(start+blocksPerRequest, len(headers))
+
+		wg.Add(1)
+		go func(batch, start, end int) {
+			defer wg.Done()
+
+			var err error
+			for attempt := 0; attempt < len(peers); attempt++ {
+				peer := peers[(batch+attempt)%len(peers)]
+				if err = requestBlocks(peer, headers[start:end], blocks[start:end]); err == nil {
+					return
+				}
+			}
+
+			lock.Lock()
+			errs = append(errs, err)
+			lock.Unlock()
+		}(batch, start, end)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return blocks, nil
+}
+
+func requestBlocks(peer blockchain.BlockChainClient, headers []*blockchain.Header, blocks []*blockchain.Block) error {
+	hashes := make([][]byte, len(headers))
+	for i, header := range headers {
+		hashes[i] = types.HashHeader(header)
+	}
+
+	stream, err := peer.GetBlocks(context.Background(), &blockchain.GetBlocksRequest{Hashes: hashes})
+	if err != nil {
+		return err
+	}
+
+	for i := range hashes {
+		block, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if block.GetHeader() == nil {
+			return fmt.Errorf("peer sent block %s without a header", hex.EncodeToString(hashes[i]))
+		}
+
+		if !bytes.Equal(types.HashBlock(block), hashes[i]) {
+			return fmt.Errorf("peer sent block %s, expected %s", hex.EncodeToString(types.HashBlock(block)), hex.EncodeToString(hashes[i]))
+		}
+		blocks[i] = block
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func freeAddress(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer ln.Close()
+
+	return ln.Addr().String()
+}
+
+func newTestValidator(t *testing.T, height int) *Server {
+	chain := NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore())
+	server := NewServer(ServerConfig{PrivateKey: crypto.GeneratePrivateKey()}, chain)
+
+	for i := 0; i < height; i++ {
+		_, err := server.createBlock()
+		require.Nil(t, err)
+	}
+
+	return server
+}
+
+func TestSyncWithPeers(t *testing.T) {
+	var (
+		height   = 2*blocksPerRequest + 3
+		source   = newTestValidator(t, height)
+		mirror   = NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		fresh    = NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		address1 = freeAddress(t)
+		address2 = freeAddress(t)
+	)
+
+	for i := 1; i <= height; i++ {
+		block, err := source.chain.GetBlockByHeight(i)
+		require.Nil(t, err)
+		require.Nil(t, mirror.chain.AddBlock(block))
+	}
+
+	go source.Start(address1, nil)
+	go mirror.Start(address2, nil)
+
+	for _, address := range []string{address1, address2} {
+		client, err := makeBlockChainClient(address)
+		require.Nil(t, err)
+		fresh.addPeer(client, &blockchain.HandshakeMessage{ListenAddress: address, Height: int32(height)})
+	}
+
+	require.Eventually(t, func() bool {
+		return fresh.chain.Height() == height
+	}, 5*time.Second, 10*time.Millisecond)
+
+	expected, err := source.chain.GetHeaderByHeight(height)
+	require.Nil(t, err)
+	actual, err := fresh.chain.GetHeaderByHeight(height)
+	require.Nil(t, err)
+	require.Equal(t, types.HashHeader(expected), types.HashHeader(actual))
+}
+
+func TestSyncFromFork(t *testing.T) {
+	var (
+		height  = 20
+		source  = newTestValidator(t, height)
+		forked  = newTestValidator(t, 0)
+		address = freeAddress(t)
+	)
+
+	// the forked node shares the first blocks, then builds its own branch
+	for i := 1; i <= 3; i++ {
+		block, err := source.chain.GetBlockByHeight(i)
+		require.Nil(t, err)
+		require.Nil(t, forked.chain.AddBlock(block))
+	}
+	for i := 0; i < 5; i++ {
+		_, err := forked.createBlock()
+		require.Nil(t, err)
+	}
+
+	go source.Start(address, nil)
+
+	client, err := makeBlockChainClient(address)
+	require.Nil(t, err)
+	forked.addPeer(client, &blockchain.HandshakeMessage{ListenAddress: address, Height: int32(height)})
+
+	require.Eventually(t, func() bool {
+		return forked.chain.Height() == height
+	}, 5*time.Second, 10*time.Millisecond)
+
+	expected, err := source.chain.GetHeaderByHeight(height)
+	require.Nil(t, err)
+	actual, err := forked.chain.GetHeaderByHeight(height)
+	require.Nil(t, err)
+	require.Equal(t, types.HashHeader(expected), types.HashHeader(actual))
+}
+
+func TestOrphanBlockTriggersSync(t *testing.T) {
+	var (
+		height  = 10
+		source  = newTestValidator(t, height)
+		node    = NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		address = freeAddress(t)
+	)
+
+	go source.Start(address, nil)
+
+	// the peer was at genesis when it shook hands
+	client, err := makeBlockChainClient(address)
+	require.Nil(t, err)
+	node.addPeer(client, &blockchain.HandshakeMessage{ListenAddress: address})
+
+	block, err := source.chain.GetBlockByHeight(height)
+	require.Nil(t, err)
+	_, err = node.HandleBlock(senderContext("127.0.0.1", address), block)
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		return node.chain.Height() == height
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 0, node.orphans.Len())
+}
+
+func senderContext(host, listenAddress string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(host), Port: 50000}})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(listenAddressKey, listenAddress))
+}
+
+func TestSenderCannotRaiseAnotherPeersHeight(t *testing.T) {
+	var (
+		node     = NewServer(ServerConfig{}, NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore()))
+		address  = freeAddress(t)
+		version  = &blockchain.HandshakeMessage{ListenAddress: address}
+		wildcard = &blockchain.HandshakeMessage{ListenAddress: ":3000"}
+	)
+
+	client, err := makeBlockChainClient(address)
+	require.Nil(t, err)
+	node.addPeer(client, version)
+	other, err := makeBlockChainClient(wildcard.ListenAddress)
+	require.Nil(t, err)
+	node.addPeer(other, wildcard)
+
+	node.updateSenderHeight(senderContext("10.0.0.1", address), 10)
+	node.updateSenderHeight(context.Background(), 10)
+	node.updateSenderHeight(metadata.NewIncomingContext(context.Background(), metadata.Pairs(listenAddressKey, address)), 10)
+	node.updateSenderHeight(senderContext("10.0.0.1", wildcard.ListenAddress), 10)
+	require.Equal(t, int32(0), version.Height)
+	require.Equal(t, int32(0), wildcard.Height)
+
+	node.updateSenderHeight(senderContext("127.0.0.1", address), 10)
+	node.updateSenderHeight(senderContext("::1", wildcard.ListenAddress), 10)
+	require.Equal(t, int32(10), version.Height)
+	require.Equal(t, int32(10), wildcard.Height)
+}
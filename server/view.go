@@ -0,0 +1,54 @@
+package server
+
+import (
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+)
+
+// utxoView layers the outputs created and spent by a sequence of transactions
+// over the outputs returned by lookup, so that a transaction can spend the
+// outputs of the transactions before it in the same block.
+type utxoView struct {
+	lookup func(types.OutPoint) (*UTXO, error)
+	utxos  map[types.OutPoint]*UTXO
+}
+
+func newUTXOView(lookup func(types.OutPoint) (*UTXO, error)) *utxoView {
+	return &utxoView{
+		lookup: lookup,
+		utxos:  make(map[types.OutPoint]*UTXO),
+	}
+}
+
+func (view *utxoView) Get(outPoint types.OutPoint) (*UTXO, error) {
+	if utxo, ok := view.utxos[outPoint]; ok {
+		return utxo, nil
+	}
+
+	return view.lookup(outPoint)
+}
+
+// apply marks the outputs spent by the transaction as spent and adds the
+// outputs it creates.
+func (view *utxoView) apply(tx *blockchain.Transaction) error {
+	if !types.IsCoinbase(tx) {
+		for _, input := range tx.Inputs {
+			utxo, err := view.Get(types.SpentOutPoint(input))
+			if err != nil {
+				return err
+			}
+
+			spent := *utxo
+			spent.Spent = true
+			view.utxos[spent.OutPoint] = &spent
+		}
+	}
+
+	hash := types.HashTransaction(tx)
+	for index := range tx.Outputs {
+		utxo := outputUTXO(tx, hash, index)
+		view.utxos[utxo.OutPoint] = utxo
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+)
+
+// maxRetargetFactor bounds how much a single adjustment changes the
+// difficulty, either way.
+const maxRetargetFactor = 4
+
+// nextDifficulty returns the difficulty of the block following parent. Every
+// RetargetInterval blocks it is scaled so the last interval would have taken
+// TargetSpacing per block. The first interval is not retargeted as the
+// genesis block has no meaningful timestamp.
+func (chain *Chain) nextDifficulty(parent *BlockNode) uint64 {
+	params := chain.genesis.Work
+	if parent.Height == 0 {
+		return params.InitialDifficulty
+	}
+
+	difficulty := parent.Header.Difficulty
+	if (parent.Height+1)%params.RetargetInterval != 0 {
+		return difficulty
+	}
+
+	first := parent
+	for i := 0; i < params.RetargetInterval && first.Parent != nil; i++ {
+		first = first.Parent
+	}
+	if first.Height == 0 {
+		return difficulty
+	}
+
+	expected := int64(params.RetargetInterval) * int64(params.TargetSpacing)
+	actual := parent.Header.Timestamp - first.Header.Timestamp
+	actual = max(actual, expected/maxRetargetFactor)
+	actual = min(actual, expected*maxRetargetFactor)
+
+	next := new(big.Int).SetUint64(difficulty)
+	next.Mul(next, big.NewInt(expected))
+	next.Div(next, big.NewInt(actual))
+
+	switch {
+	case !next.IsUint64():
+		return math.MaxUint64
+	case next.Sign() == 0:
+		return 1
+	}
+	return next.Uint64()
+}
+
+// checkWork checks that the header carries the difficulty scheduled after
+// parent and that its hash meets it.
+func (chain *Chain) checkWork(header *blockchain.Header, parent *BlockNode) error {
+	if difficulty := chain.nextDifficulty(parent); header.Difficulty != difficulty {
+		return fmt.Errorf("block difficulty %d does not match the scheduled difficulty %d", header.Difficulty, difficulty)
+	}
+
+	if !types.CheckWork(header) {
+		return fmt.Errorf("block hash does not meet its difficulty target")
+	}
+
+	return nil
+}
+
+// Mine sets the scheduled difficulty and the root hash of a block assembled by
+// NewBlock and searches for a nonce. It reports whether it found one before
+// stop returned true.
+func (chain *Chain) Mine(block *blockchain.Block, stop func() bool) bool {
+	chain.lock.Lock()
+	parent, ok := chain.index[hex.EncodeToString(block.Header.PreviousHash)]
+	chain.lock.Unlock()
+	if !ok {
+		return false
+	}
+
+	block.Header.Difficulty = chain.nextDifficulty(parent)
+	types.SetRootHash(block)
+
+	return types.MineHeader(block.Header, stop)
+}
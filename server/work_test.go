@@ -0,0 +1,150 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/types"
+	"github.com/stretchr/testify/require"
+)
+
+var testWorkGenesis = Genesis{
+	Consensus: ConsensusWork,
+	Work: WorkParams{
+		InitialDifficulty: 16,
+		RetargetInterval:  4,
+		TargetSpacing:     time.Second,
+	},
+}
+
+func never() bool {
+	return false
+}
+
+// mineChild mines an unsigned block on top of parent with the given timestamp.
+func mineChild(t *testing.T, chain *Chain, parent *blockchain.Block, timestamp time.Duration) *blockchain.Block {
+	block := &blockchain.Block{
+		Header: &blockchain.Header{
+			Version:      1,
+			Height:       parent.Header.Height + 1,
+			PreviousHash: types.HashBlock(parent),
+			Timestamp:    int64(timestamp),
+		},
+	}
+	require.True(t, chain.Mine(block, never))
+
+	return block
+}
+
+func TestProofOfWork(t *testing.T) {
+	var (
+		config = DiskStoreConfig{Dir: t.TempDir()}
+		tip    *blockchain.Block
+	)
+
+	store, err := OpenDiskStore(config)
+	require.Nil(t, err)
+
+	chain, err := OpenChain(store.Blocks, store.Transactions, store.UTXOs, WithGenesis(testWorkGenesis))
+	require.Nil(t, err)
+
+	tip, err = chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	for height := 1; height <= 3; height++ {
+		block, err := chain.NewBlock(nil, nil)
+		require.Nil(t, err)
+		require.True(t, chain.Mine(block, never))
+		require.Equal(t, uint64(16), block.Header.Difficulty)
+		require.Nil(t, block.Signature)
+
+		require.Nil(t, chain.AddBlock(block))
+		tip = block
+	}
+
+	// a nonce that does not meet the target
+	next := time.Duration(tip.Header.Timestamp + 1)
+	block := mineChild(t, chain, tip, next)
+	for types.CheckWork(block.Header) {
+		block.Header.Nonce++
+	}
+	require.NotNil(t, chain.AddBlock(block))
+
+	// enough work for a lower difficulty than the scheduled one
+	block.Header.Difficulty = 1
+	require.True(t, types.MineHeader(block.Header, never))
+	require.NotNil(t, chain.AddBlock(block))
+
+	require.Nil(t, chain.AddBlock(mineChild(t, chain, tip, next)))
+	require.Nil(t, store.Close())
+
+	store, err = OpenDiskStore(config)
+	require.Nil(t, err)
+	defer store.Close()
+
+	chain, err = OpenChain(store.Blocks, store.Transactions, store.UTXOs, WithGenesis(testWorkGenesis))
+	require.Nil(t, err)
+	require.Equal(t, 4, chain.Height())
+
+	// the same blocks carry no valid signature
+	_, err = OpenChain(store.Blocks, store.Transactions, store.UTXOs)
+	var corruption *ChainCorruptionError
+	require.ErrorAs(t, err, &corruption)
+}
+
+func TestDifficultyRetarget(t *testing.T) {
+	chain := NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithGenesis(testWorkGenesis))
+
+	tip, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+
+	// the first interval starts at the genesis block and is not retargeted
+	for height := 1; height <= 7; height++ {
+		tip = mineChild(t, chain, tip, time.Duration(height)*2*time.Second)
+		require.Nil(t, chain.AddBlock(tip))
+		require.Equal(t, uint64(16), tip.Header.Difficulty)
+	}
+
+	// blocks came twice as slow as targeted
+	for height := 8; height <= 11; height++ {
+		tip = mineChild(t, chain, tip, 14*time.Second+time.Duration(height-7)*time.Millisecond)
+		require.Nil(t, chain.AddBlock(tip))
+		require.Equal(t, uint64(8), tip.Header.Difficulty)
+	}
+
+	// far too fast, the adjustment is bounded
+	tip = mineChild(t, chain, tip, 14*time.Second+5*time.Millisecond)
+	require.Nil(t, chain.AddBlock(tip))
+	require.Equal(t, uint64(8*maxRetargetFactor), tip.Header.Difficulty)
+}
+
+func TestMostWorkForkChoice(t *testing.T) {
+	chain := NewChain(NewMemoryBlockStore(), NewMemoryTxStore(), NewMemoryUTXOStore(), WithGenesis(testWorkGenesis))
+
+	fork, err := chain.GetBlockByHeight(0)
+	require.Nil(t, err)
+	for height := 1; height <= 3; height++ {
+		fork = mineChild(t, chain, fork, time.Duration(height)*time.Second)
+		require.Nil(t, chain.AddBlock(fork))
+	}
+
+	// a slow branch retargets down and grows longer
+	slow := fork
+	for height := 4; height <= 9; height++ {
+		slow = mineChild(t, chain, slow, time.Duration(height)*4*time.Second)
+		require.Nil(t, chain.AddBlock(slow))
+	}
+	require.Equal(t, uint64(4), slow.Header.Difficulty)
+	require.Equal(t, 9, chain.Height())
+
+	// a fast branch retargets up and holds more work at a lower height
+	fast := fork
+	for height := 4; height <= 8; height++ {
+		fast = mineChild(t, chain, fast, 3*time.Second+time.Duration(height-3)*250*time.Millisecond)
+		require.Nil(t, chain.AddBlock(fast))
+	}
+	require.Equal(t, uint64(64), fast.Header.Difficulty)
+	require.Equal(t, 8, chain.Height())
+	require.Equal(t, types.HashBlock(fast), types.HashHeader(chain.tip.Header))
+}
@@ -49,14 +49,7 @@ func VerifyBlock(block *blockchain.Block) bool {
 }
 
 func SignBlock(privateKey *crypto.PrivateKey, block *blockchain.Block) *crypto.Signature {
-	if len(block.Transactions) > 0 {
-		tree, err := getMerkleTree(block)
-		if err != nil {
-			panic(err)
-		}
-
-		block.Header.RootHash = tree.MerkleRoot()
-	}
+	SetRootHash(block)
 
 	hash := HashBlock(block)
 	signature := privateKey.Sign(hash)
@@ -66,6 +59,26 @@ func SignBlock(privateKey *crypto.PrivateKey, block *blockchain.Block) *crypto.S
 	return signature
 }
 
+// SetRootHash sets the merkle root of the block transactions in its header.
+func SetRootHash(block *blockchain.Block) {
+	if len(block.Transactions) == 0 {
+		return
+	}
+
+	tree, err := getMerkleTree(block)
+	if err != nil {
+		panic(err)
+	}
+
+	block.Header.RootHash = tree.MerkleRoot()
+}
+
+// VerifyRootHash checks the merkle root of the block transactions without
+// checking the block signature.
+func VerifyRootHash(block *blockchain.Block) bool {
+	return len(block.Transactions) == 0 || verifyRootHash(block)
+}
+
 func verifyRootHash(block *blockchain.Block) bool {
 	tree, err := getMerkleTree(block)
 	if err != nil {
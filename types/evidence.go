@@ -0,0 +1,57 @@
+package types
+
+import (
+	"bytes"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+)
+
+// SignedHeaderOf returns the header of a signed block along with its
+// signature.
+func SignedHeaderOf(block *blockchain.Block) *blockchain.SignedHeader {
+	return &blockchain.SignedHeader{
+		Header:    block.Header,
+		PublicKey: block.PublicKey,
+		Signature: block.Signature,
+	}
+}
+
+func NewEvidenceTransaction(first, second *blockchain.Block) *blockchain.Transaction {
+	return &blockchain.Transaction{
+		Version: 1,
+		Kind:    blockchain.Transaction_EVIDENCE,
+		Evidence: &blockchain.Evidence{
+			First:  SignedHeaderOf(first),
+			Second: SignedHeaderOf(second),
+		},
+	}
+}
+
+func VerifySignedHeader(header *blockchain.SignedHeader) bool {
+	if header.Header == nil || len(header.PublicKey) != crypto.PublicKeyLen || len(header.Signature) != crypto.SignatureLen {
+		return false
+	}
+
+	signature := crypto.SignatureFromBytes(header.Signature)
+	return signature.Verify(crypto.PublicKeyFromBytes(header.PublicKey), HashHeader(header.Header))
+}
+
+// VerifyEvidence reports whether the evidence holds two different headers at
+// the same height, both validly signed by the same key.
+func VerifyEvidence(evidence *blockchain.Evidence) bool {
+	first, second := evidence.GetFirst(), evidence.GetSecond()
+	if first == nil || second == nil {
+		return false
+	}
+
+	if !bytes.Equal(first.PublicKey, second.PublicKey) {
+		return false
+	}
+
+	if !VerifySignedHeader(first) || !VerifySignedHeader(second) {
+		return false
+	}
+
+	return first.Header.Height == second.Header.Height && !bytes.Equal(HashHeader(first.Header), HashHeader(second.Header))
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/blockchain/crypto"
+	"github.com/blockchain/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEvidence(t *testing.T) {
+	var (
+		privateKey = crypto.GeneratePrivateKey()
+		first      = util.RandomBlock()
+		second     = util.RandomBlock()
+	)
+	second.Header.Height = first.Header.Height
+	SignBlock(privateKey, first)
+	SignBlock(privateKey, second)
+
+	tx := NewEvidenceTransaction(first, second)
+	require.True(t, VerifyEvidence(tx.Evidence))
+
+	// the same header twice
+	require.False(t, VerifyEvidence(NewEvidenceTransaction(first, first).Evidence))
+
+	// headers at different heights
+	second.Header.Height++
+	SignBlock(privateKey, second)
+	require.False(t, VerifyEvidence(NewEvidenceTransaction(first, second).Evidence))
+
+	// headers signed by different keys
+	second.Header.Height--
+	SignBlock(crypto.GeneratePrivateKey(), second)
+	require.False(t, VerifyEvidence(NewEvidenceTransaction(first, second).Evidence))
+
+	// a forged signature
+	SignBlock(privateKey, second)
+	second.Header.Timestamp++
+	require.False(t, VerifyEvidence(NewEvidenceTransaction(first, second).Evidence))
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	blockchain "github.com/blockchain/proto"
+)
+
+// OutPoint identifies a transaction output by the hex encoded hash of its
+// transaction and its index in the transaction outputs.
+type OutPoint struct {
+	TxHash string
+	Index  uint32
+}
+
+func NewOutPoint(txHash []byte, index uint32) OutPoint {
+	return OutPoint{
+		TxHash: hex.EncodeToString(txHash),
+		Index:  index,
+	}
+}
+
+// SpentOutPoint returns the output spent by the given input.
+func SpentOutPoint(input *blockchain.TxInput) OutPoint {
+	return NewOutPoint(input.PreviousTxHash, input.PreviousOutIndex)
+}
+
+func (outPoint OutPoint) Hash() []byte {
+	hash, err := hex.DecodeString(outPoint.TxHash)
+	if err != nil {
+		panic(err)
+	}
+
+	return hash
+}
+
+func (outPoint OutPoint) String() string {
+	return fmt.Sprintf("%s_%d", outPoint.TxHash, outPoint.Index)
+}
+
+func (outPoint OutPoint) Proto() *blockchain.OutPoint {
+	return &blockchain.OutPoint{
+		TxHash: outPoint.Hash(),
+		Index:  outPoint.Index,
+	}
+}
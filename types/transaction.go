@@ -8,8 +8,10 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// SignTransaction signs the transaction with all input signatures left out,
+// so every input of a transaction signs the same message.
 func SignTransaction(privatekey *crypto.PrivateKey, tx *blockchain.Transaction) *crypto.Signature {
-	return privatekey.Sign(HashTransaction(tx))
+	return privatekey.Sign(hashForSigning(tx))
 }
 
 func HashTransaction(tx *blockchain.Transaction) []byte {
@@ -23,20 +25,54 @@ func HashTransaction(tx *blockchain.Transaction) []byte {
 }
 
 func VerifyTransaction(tx *blockchain.Transaction) bool {
+	hash := hashForSigning(tx)
+
 	for _, input := range tx.Inputs {
+		if len(input.Signature) != crypto.SignatureLen || len(input.PublicKey) != crypto.PublicKeyLen {
+			return false
+		}
+
 		var (
 			signature = crypto.SignatureFromBytes(input.Signature)
 			publicKey = crypto.PublicKeyFromBytes(input.PublicKey)
 		)
 
-		// TODO: check issues of nil signature
-		tmpSignature := input.Signature
-		input.Signature = nil
-
-		if !signature.Verify(publicKey, HashTransaction(tx)) {
+		if !signature.Verify(publicKey, hash) {
 			return false
 		}
-		input.Signature = tmpSignature
 	}
 	return true
 }
+
+func hashForSigning(tx *blockchain.Transaction) []byte {
+	unsigned := proto.Clone(tx).(*blockchain.Transaction)
+	for _, input := range unsigned.Inputs {
+		input.Signature = nil
+	}
+
+	return HashTransaction(unsigned)
+}
+
+// NewCoinbaseTransaction creates the transaction minting the block reward. Its
+// single input spends nothing and carries the block height, which keeps the
+// hashes of coinbases paying the same amount to the same address unique.
+func NewCoinbaseTransaction(address []byte, amount int64, height int32) *blockchain.Transaction {
+	return &blockchain.Transaction{
+		Version: 1,
+		Inputs: []*blockchain.TxInput{
+			{
+				PreviousOutIndex: uint32(height),
+			},
+		},
+		Outputs: []*blockchain.TxOutput{
+			{
+				Amount:  amount,
+				Address: address,
+			},
+		},
+	}
+}
+
+func IsCoinbase(tx *blockchain.Transaction) bool {
+	return len(tx.Inputs) == 1 && len(tx.Inputs[0].PreviousTxHash) == 0
+}
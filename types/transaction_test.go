@@ -43,3 +43,63 @@ func TestNewTransaction(t *testing.T) {
 
 	require.True(t, VerifyTransaction(tx))
 }
+
+func TestVerifyUnsignedTransaction(t *testing.T) {
+	privateKey := crypto.GeneratePrivateKey()
+	tx := &blockchain.Transaction{
+		Version: 1,
+		Inputs: []*blockchain.TxInput{
+			{
+				PreviousTxHash: util.RandomHash(),
+				PublicKey:      privateKey.Public().Bytes(),
+			},
+		},
+	}
+
+	require.False(t, VerifyTransaction(tx))
+}
+
+func TestVerifyMultiInputTransaction(t *testing.T) {
+	var (
+		privateKey1 = crypto.GeneratePrivateKey()
+		privateKey2 = crypto.GeneratePrivateKey()
+	)
+
+	tx := &blockchain.Transaction{
+		Version: 1,
+		Inputs: []*blockchain.TxInput{
+			{
+				PreviousTxHash: util.RandomHash(),
+				PublicKey:      privateKey1.Public().Bytes(),
+			},
+			{
+				PreviousTxHash:   util.RandomHash(),
+				PreviousOutIndex: 1,
+				PublicKey:        privateKey2.Public().Bytes(),
+			},
+		},
+	}
+	tx.Inputs[0].Signature = SignTransaction(privateKey1, tx).Bytes()
+	tx.Inputs[1].Signature = SignTransaction(privateKey2, tx).Bytes()
+
+	require.True(t, VerifyTransaction(tx))
+
+	tx.Inputs[1].PublicKey = privateKey1.Public().Bytes()
+	require.False(t, VerifyTransaction(tx))
+}
+
+func TestCoinbaseTransaction(t *testing.T) {
+	address := crypto.GeneratePrivateKey().Public().Address().Bytes()
+
+	coinbase1 := NewCoinbaseTransaction(address, 50, 1)
+	coinbase2 := NewCoinbaseTransaction(address, 50, 2)
+
+	require.True(t, IsCoinbase(coinbase1))
+	require.NotEqual(t, HashTransaction(coinbase1), HashTransaction(coinbase2))
+
+	tx := &blockchain.Transaction{
+		Version: 1,
+		Inputs:  []*blockchain.TxInput{{PreviousTxHash: util.RandomHash()}},
+	}
+	require.False(t, IsCoinbase(tx))
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"crypto/sha256"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// HashVote hashes the vote with its signature left out, which is the message
+// the validator signs.
+func HashVote(vote *blockchain.Vote) []byte {
+	unsigned := proto.Clone(vote).(*blockchain.Vote)
+	unsigned.Signature = nil
+
+	b, err := proto.Marshal(unsigned)
+	if err != nil {
+		panic(err)
+	}
+
+	hash := sha256.Sum256(b)
+	return hash[:]
+}
+
+func SignVote(privateKey *crypto.PrivateKey, vote *blockchain.Vote) *crypto.Signature {
+	vote.PublicKey = privateKey.Public().Bytes()
+	signature := privateKey.Sign(HashVote(vote))
+	vote.Signature = signature.Bytes()
+
+	return signature
+}
+
+func VerifyVote(vote *blockchain.Vote) bool {
+	if len(vote.PublicKey) != crypto.PublicKeyLen || len(vote.Signature) != crypto.SignatureLen {
+		return false
+	}
+
+	signature := crypto.SignatureFromBytes(vote.Signature)
+	return signature.Verify(crypto.PublicKeyFromBytes(vote.PublicKey), HashVote(vote))
+}
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/blockchain/crypto"
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerifyVote(t *testing.T) {
+	var (
+		privateKey = crypto.GeneratePrivateKey()
+		vote       = &blockchain.Vote{
+			Type:      blockchain.Vote_PRECOMMIT,
+			Height:    3,
+			Round:     1,
+			BlockHash: util.RandomHash(),
+		}
+	)
+
+	require.False(t, VerifyVote(vote))
+
+	SignVote(privateKey, vote)
+	require.True(t, VerifyVote(vote))
+	require.Equal(t, privateKey.Public().Bytes(), vote.PublicKey)
+
+	vote.Round = 2
+	require.False(t, VerifyVote(vote))
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"math/big"
+
+	blockchain "github.com/blockchain/proto"
+)
+
+// maxTarget is the target of a header with difficulty 1, any hash meets it.
+var maxTarget = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// Target returns the value the hash of a header with the given difficulty may
+// not exceed.
+func Target(difficulty uint64) *big.Int {
+	if difficulty == 0 {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Div(maxTarget, new(big.Int).SetUint64(difficulty))
+}
+
+// CheckWork reports whether the hash of the header meets the target of its
+// difficulty.
+func CheckWork(header *blockchain.Header) bool {
+	if header.Difficulty == 0 {
+		return false
+	}
+
+	hash := new(big.Int).SetBytes(HashHeader(header))
+	return hash.Cmp(Target(header.Difficulty)) <= 0
+}
+
+// MineHeader searches for a nonce meeting the difficulty of the header and
+// reports whether it found one before stop returned true. Stop is called every
+// few thousand nonces.
+func MineHeader(header *blockchain.Header, stop func() bool) bool {
+	if header.Difficulty == 0 {
+		return false
+	}
+
+	target := Target(header.Difficulty)
+	hash := new(big.Int)
+
+	for nonce := header.Nonce; ; nonce++ {
+		if nonce%4096 == 0 && stop() {
+			return false
+		}
+
+		header.Nonce = nonce
+		if hash.SetBytes(HashHeader(header)).Cmp(target) <= 0 {
+			return true
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	blockchain "github.com/blockchain/proto"
+	"github.com/blockchain/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMineHeader(t *testing.T) {
+	header := &blockchain.Header{
+		Version:      1,
+		Height:       1,
+		PreviousHash: util.RandomHash(),
+		Difficulty:   1 << 10,
+	}
+
+	require.True(t, MineHeader(header, func() bool { return false }))
+	require.True(t, CheckWork(header))
+	require.LessOrEqual(t, new(big.Int).SetBytes(HashHeader(header)).Cmp(Target(header.Difficulty)), 0)
+
+	header.Difficulty = 1 << 40
+	require.False(t, CheckWork(header))
+	require.False(t, MineHeader(header, func() bool { return true }))
+
+	header.Difficulty = 0
+	require.False(t, CheckWork(header))
+}